@@ -48,6 +48,46 @@ func (c *Client) UploadStemcell(url, sha1 string) (Task, error) {
 	return task, nil
 }
 
+// DeleteStemcell deletes the stemcell version from the given BOSH. Unless
+// forced, it refuses to delete a stemcell that deployments use.
+func (c *Client) DeleteStemcell(name, version string, force bool) (Task, error) {
+	if !force {
+		stemcells, err := c.GetStemcells()
+		if err != nil {
+			return Task{}, err
+		}
+		found := false
+		for _, stemcell := range stemcells {
+			if stemcell.Name != name || stemcell.Version != version {
+				continue
+			}
+			found = true
+			if len(stemcell.Deployments) > 0 {
+				var names []string
+				for _, d := range stemcell.Deployments {
+					names = append(names, d.Name)
+				}
+				return Task{}, fmt.Errorf("stemcell %s/%s is used by deployments %s", name, version, strings.Join(names, ", "))
+			}
+		}
+		if !found {
+			return Task{}, fmt.Errorf("stemcell %s/%s not found", name, version)
+		}
+	}
+
+	path := "/stemcells/" + url.PathEscape(name) + "/" + url.PathEscape(version)
+	if force {
+		path += "?force=true"
+	}
+	r := c.NewRequest("DELETE", path)
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error deleting stemcell %s/%s: %w", name, version, err)
+	}
+	return task, nil
+}
+
 // GetReleases from the given BOSH
 func (c *Client) GetReleases() ([]Release, error) {
 	r := c.NewRequest("GET", "/releases")
@@ -85,6 +125,55 @@ func (c *Client) UploadRelease(url, sha1 string) (Task, error) {
 	return task, nil
 }
 
+// DeleteRelease deletes the release version from the given BOSH, or every
+// version of the release when version is empty. Unless forced, it refuses to
+// delete a release version that is currently deployed.
+func (c *Client) DeleteRelease(name, version string, force bool) (Task, error) {
+	if !force {
+		releases, err := c.GetReleases()
+		if err != nil {
+			return Task{}, err
+		}
+		found := false
+		for _, release := range releases {
+			if release.Name != name {
+				continue
+			}
+			for _, rv := range release.ReleaseVersions {
+				if version != "" && rv.Version != version {
+					continue
+				}
+				found = true
+				if rv.CurrentlyDeployed {
+					return Task{}, fmt.Errorf("release %s/%s is currently deployed", name, rv.Version)
+				}
+			}
+		}
+		if !found {
+			return Task{}, fmt.Errorf("release %s not found", strings.TrimSuffix(name+"/"+version, "/"))
+		}
+	}
+
+	query := url.Values{}
+	if version != "" {
+		query.Set("version", version)
+	}
+	if force {
+		query.Set("force", "true")
+	}
+	path := "/releases/" + url.PathEscape(name)
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	r := c.NewRequest("DELETE", path)
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error deleting release %s: %w", name, err)
+	}
+	return task, nil
+}
+
 // GetDeployments returns all deployments from the given BOSH
 func (c *Client) GetDeployments() ([]Deployment, error) {
 	r := c.NewRequest("GET", "/deployments")
@@ -133,36 +222,110 @@ func (c *Client) CreateDeployment(manifest string) (Task, error) {
 	return task, nil
 }
 
+// VMsOptions configures how the VMs of a deployment are listed. The zero
+// value asks for the basic listing, use DefaultVMsOptions for the full one
+// returned by GetDeploymentVMs.
+type VMsOptions struct {
+	// Full requests vitals and processes for every VM through a director task.
+	// The basic listing is returned directly and is much cheaper to produce,
+	// but it leaves out the VM type, job state, vitals and processes, and the
+	// ignore and resurrection state of the instances.
+	Full bool
+	// TaskWaitOptions bounds the wait for the full listing task, which defaults to 5 minutes
+	TaskWaitOptions
+}
+
+// DefaultVMsOptions returns the options used by GetDeploymentVMs
+func DefaultVMsOptions() VMsOptions {
+	return VMsOptions{
+		Full: true,
+		TaskWaitOptions: TaskWaitOptions{
+			Timeout:      time.Minute * 5,
+			PollInterval: time.Second,
+		},
+	}
+}
+
 // GetDeploymentVMs returns all the VMs that make up the specified deployment
 func (c *Client) GetDeploymentVMs(name string) ([]VM, error) {
-	r := c.NewRequest("GET", "/deployments/"+name+"/vms?format=full")
+	return c.GetDeploymentVMsWithOptions(name, DefaultVMsOptions())
+}
+
+// GetDeploymentVMsWithOptions returns all the VMs that make up the specified
+// deployment using the given listing format and task wait settings. Unlike
+// GetDeploymentVMs, VMsOptions{} returns the basic listing.
+func (c *Client) GetDeploymentVMsWithOptions(name string, opts VMsOptions) ([]VM, error) {
+	var vms []VM
+	err := c.StreamDeploymentVMs(name, opts, func(vm VM) error {
+		vms = append(vms, vm)
+		return nil
+	})
+	if err != nil {
+		return []VM{}, err
+	}
+	return vms, nil
+}
+
+// StreamDeploymentVMs calls fn with each VM of the specified deployment as
+// soon as it is decoded. Returning an error from fn stops the listing.
+func (c *Client) StreamDeploymentVMs(name string, opts VMsOptions, fn func(VM) error) error {
+	if !opts.Full {
+		return c.streamBasicDeploymentVMs(name, fn)
+	}
+
+	return c.streamFullDeploymentRows(name, "vms", opts, fn)
+}
+
+// streamFullDeploymentRows lists the deployment VMs or instances, which share
+// the same format, through a director task
+func (c *Client) streamFullDeploymentRows(name, kind string, opts VMsOptions, fn func(VM) error) error {
+	r := c.NewRequest("GET", "/deployments/"+name+"/"+kind+"?format=full")
 	var task Task
 	err := c.DoRequestAndUnmarshal(r, &task)
 	if err != nil {
-		return []VM{}, fmt.Errorf("error requesting deployment %s VMs: %w", name, err)
+		return fmt.Errorf("error requesting deployment %s %s: %w", name, kind, err)
 	}
 
-	task, err = c.WaitUntilDone(task, time.Minute*5)
+	task, err = c.WaitUntilDoneWithInterval(task, opts.timeout(time.Minute*5), opts.pollInterval())
 	if err != nil {
-		return []VM{}, fmt.Errorf("error waiting for deployment %s VM task to complete: %w", name, err)
+		return fmt.Errorf("error waiting for deployment %s %s task to complete: %w", name, kind, err)
 	}
 
-	var vms []VM
-	output, err := c.GetTaskResult(task.ID)
+	output, err := c.getTaskOutputReader(task.ID, "result")
 	if err != nil {
-		return []VM{}, fmt.Errorf("error getting deployment %s VMs task result: %w", name, err)
+		return fmt.Errorf("error getting deployment %s %s task result: %w", name, kind, err)
 	}
-	for _, value := range output {
-		if len(value) > 0 {
-			var vm VM
-			err = json.Unmarshal([]byte(value), &vm)
-			if err != nil {
-				return []VM{}, fmt.Errorf("error unmarshalling deployment %s VMs response: %w", name, err)
-			}
-			vms = append(vms, vm)
+	defer func() { _ = output.Close() }()
+
+	dec := json.NewDecoder(output)
+	for {
+		var vm VM
+		err = dec.Decode(&vm)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error unmarshalling deployment %s %s response: %w", name, kind, err)
+		}
+		if err = fn(vm); err != nil {
+			return err
 		}
 	}
-	return vms, nil
+}
+
+func (c *Client) streamBasicDeploymentVMs(name string, fn func(VM) error) error {
+	r := c.NewRequest("GET", "/deployments/"+name+"/vms")
+	var basicVMs []basicVM
+	err := c.DoRequestAndUnmarshal(r, &basicVMs)
+	if err != nil {
+		return fmt.Errorf("error requesting deployment %s VMs: %w", name, err)
+	}
+	for _, b := range basicVMs {
+		if err = fn(b.toVM()); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // GetTasksByQuery from given BOSH
@@ -196,15 +359,13 @@ func (c *Client) GetTask(id int) (Task, error) {
 
 // GetTaskOutput returns the completed tasks output
 func (c *Client) GetTaskOutput(id int, typ string) ([]string, error) {
-	r := c.NewRequest("GET", "/tasks/"+strconv.Itoa(id)+"/output?type="+typ)
-
-	res, err := c.DoRequest(r)
+	output, err := c.getTaskOutputReader(id, typ)
 	if err != nil {
-		return []string{}, fmt.Errorf("error requesting task output: %w", err)
+		return []string{}, err
 	}
-	defer func() { _ = res.Body.Close() }()
+	defer func() { _ = output.Close() }()
 
-	b, err := io.ReadAll(res.Body)
+	b, err := io.ReadAll(output)
 	if err != nil {
 		return []string{}, fmt.Errorf("error reading task output response: %w", err)
 	}
@@ -212,6 +373,18 @@ func (c *Client) GetTaskOutput(id int, typ string) ([]string, error) {
 	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n"), nil
 }
 
+// getTaskOutputReader returns the unread body of the tasks output, which the
+// caller must close
+func (c *Client) getTaskOutputReader(id int, typ string) (io.ReadCloser, error) {
+	r := c.NewRequest("GET", "/tasks/"+strconv.Itoa(id)+"/output?type="+typ)
+
+	res, err := c.DoRequest(r)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting task output: %w", err)
+	}
+	return res.Body, nil
+}
+
 // GetTaskResult returns the tasks result
 func (c *Client) GetTaskResult(id int) ([]string, error) {
 	return c.GetTaskOutput(id, "result")
@@ -237,13 +410,7 @@ func (c *Client) GetTaskEvents(id int) ([]TaskEvent, error) {
 
 // GetCloudConfig from given BOSH
 func (c *Client) GetCloudConfig(latest bool) ([]Cfg, error) {
-	qs := "?latest=true"
-	if !latest {
-		qs = "?latest=false"
-	}
-	r := c.NewRequest("GET", "/configs"+qs)
-	var cfg []Cfg
-	err := c.DoRequestAndUnmarshal(r, &cfg)
+	cfg, err := c.GetConfigs(ConfigsFilter{Type: ConfigTypeCloud, Latest: latest})
 	if err != nil {
 		return []Cfg{}, fmt.Errorf("error cloud config: %w", err)
 	}
@@ -252,30 +419,10 @@ func (c *Client) GetCloudConfig(latest bool) ([]Cfg, error) {
 
 // UpdateCloudConfig updates the cloud config with the specified config
 func (c *Client) UpdateCloudConfig(config string) error {
-	r := c.NewRequest("POST", "/configs")
-	in := struct {
-		Name    string `json:"name"`
-		Type    string `json:"type"`
-		Content string `json:"content"`
-	}{
-		Name:    "default",
-		Type:    "cloud",
-		Content: config,
-	}
-	b, err := json.Marshal(&in)
-	if err != nil {
-		return fmt.Errorf("error marshalling the cloud config update: %w", err)
-	}
-
-	r.body = bytes.NewBuffer(b)
-	r.header["Content-Type"] = "application/json"
-
-	resp, err := c.DoRequest(r)
+	_, err := c.UpdateConfig(ConfigTypeCloud, "default", config)
 	if err != nil {
 		return fmt.Errorf("error updating the cloud config: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
-
 	return nil
 }
 
@@ -303,39 +450,124 @@ func (c *Client) Cleanup(removeAll bool) (Task, error) {
 	return task, nil
 }
 
+// Instance states accepted by ChangeInstanceState
+const (
+	InstanceStateStarted  = "started"
+	InstanceStateStopped  = "stopped"
+	InstanceStateDetached = "detached"
+	InstanceStateRestart  = "restart"
+	InstanceStateRecreate = "recreate"
+)
+
+// VMActionOptions configures how an instance state change is rolled out
+type VMActionOptions struct {
+	// SkipDrain skips running the drain scripts of the jobs
+	SkipDrain bool
+	// Fix recreates instances with unresponsive agents
+	Fix bool
+	// Canaries overrides the manifest canaries, e.g. "1" or "10%"; only used when converging
+	Canaries string
+	// MaxInFlight overrides the manifest max_in_flight, e.g. "3" or "25%"; only used when converging
+	MaxInFlight string
+}
+
 func (c *Client) Restart(deployment, instanceGroup, instanceID string) (Task, error) {
-	return c.vmAction("restart", deployment, instanceGroup, instanceID, true)
+	return c.vmAction("restart", deployment, instanceGroup, instanceID, true, VMActionOptions{})
 }
 
 func (c *Client) RestartNoConverge(deployment, instanceGroup, instanceID string) (Task, error) {
-	return c.vmAction("restart", deployment, instanceGroup, instanceID, false)
+	return c.vmAction("restart", deployment, instanceGroup, instanceID, false, VMActionOptions{})
 }
 
 func (c *Client) Stop(deployment, instanceGroup, instanceID string) (Task, error) {
-	return c.vmAction("stopped", deployment, instanceGroup, instanceID, true)
+	return c.vmAction("stopped", deployment, instanceGroup, instanceID, true, VMActionOptions{})
 }
 
 func (c *Client) StopNoConverge(deployment, instanceGroup, instanceID string) (Task, error) {
-	return c.vmAction("stopped", deployment, instanceGroup, instanceID, false)
+	return c.vmAction("stopped", deployment, instanceGroup, instanceID, false, VMActionOptions{})
+}
+
+// StopHard stops the instance and deletes its VM, keeping the persistent disk
+func (c *Client) StopHard(deployment, instanceGroup, instanceID string) (Task, error) {
+	return c.vmAction("detached", deployment, instanceGroup, instanceID, true, VMActionOptions{})
+}
+
+// StopHardNoConverge stops the instance and deletes its VM without converging the deployment
+func (c *Client) StopHardNoConverge(deployment, instanceGroup, instanceID string) (Task, error) {
+	return c.vmAction("detached", deployment, instanceGroup, instanceID, false, VMActionOptions{})
 }
 
 func (c *Client) Start(deployment, instanceGroup, instanceID string) (Task, error) {
-	return c.vmAction("started", deployment, instanceGroup, instanceID, true)
+	return c.vmAction("started", deployment, instanceGroup, instanceID, true, VMActionOptions{})
 }
 
 func (c *Client) StartNoConverge(deployment, instanceGroup, instanceID string) (Task, error) {
-	return c.vmAction("started", deployment, instanceGroup, instanceID, false)
+	return c.vmAction("started", deployment, instanceGroup, instanceID, false, VMActionOptions{})
+}
+
+// Recreate deletes and recreates the VM of the instance
+func (c *Client) Recreate(deployment, instanceGroup, instanceID string) (Task, error) {
+	return c.vmAction("recreate", deployment, instanceGroup, instanceID, true, VMActionOptions{})
+}
+
+// RecreateNoConverge deletes and recreates the VM of the instance without converging the deployment
+func (c *Client) RecreateNoConverge(deployment, instanceGroup, instanceID string) (Task, error) {
+	return c.vmAction("recreate", deployment, instanceGroup, instanceID, false, VMActionOptions{})
+}
+
+// ChangeInstanceState converges the instance to the given state. An empty
+// instanceID targets the whole instance group and an empty instanceGroup
+// targets the whole deployment.
+func (c *Client) ChangeInstanceState(deployment, instanceGroup, instanceID, state string, opts VMActionOptions) (Task, error) {
+	return c.vmAction(state, deployment, instanceGroup, instanceID, true, opts)
+}
+
+// ChangeInstanceStateNoConverge applies the state to a single instance without
+// converging the rest of the deployment
+func (c *Client) ChangeInstanceStateNoConverge(deployment, instanceGroup, instanceID, state string, opts VMActionOptions) (Task, error) {
+	return c.vmAction(state, deployment, instanceGroup, instanceID, false, opts)
 }
 
-func (c *Client) vmAction(action, deployment, instanceGroup, instanceID string, converge bool) (Task, error) {
+func (c *Client) vmAction(action, deployment, instanceGroup, instanceID string, converge bool, opts VMActionOptions) (Task, error) {
+	query := url.Values{}
+	if opts.SkipDrain {
+		query.Set("skip_drain", "true")
+	}
+	if opts.Fix {
+		query.Set("fix", "true")
+	}
+
 	var p string
 	if converge {
-		p = fmt.Sprintf("/deployments/%s/jobs/%s/%s?state=%s",
-			deployment, instanceGroup, instanceID, action)
+		query.Set("state", action)
+		if opts.Canaries != "" {
+			query.Set("canaries", opts.Canaries)
+		}
+		if opts.MaxInFlight != "" {
+			query.Set("max_in_flight", opts.MaxInFlight)
+		}
+		switch {
+		case instanceGroup == "":
+			p = fmt.Sprintf("/deployments/%s/jobs/*", deployment)
+		case instanceID == "":
+			p = fmt.Sprintf("/deployments/%s/jobs/%s", deployment, instanceGroup)
+		default:
+			p = fmt.Sprintf("/deployments/%s/jobs/%s/%s", deployment, instanceGroup, instanceID)
+		}
 	} else {
+		if instanceGroup == "" || instanceID == "" {
+			return Task{}, fmt.Errorf("error creating VM %s task: an instance group and instance ID are required without converge", action)
+		}
+		if action == InstanceStateDetached {
+			action = InstanceStateStopped
+			query.Set("hard", "true")
+		}
 		p = fmt.Sprintf("/deployments/%s/instance_groups/%s/%s/actions/%s",
 			deployment, instanceGroup, instanceID, action)
 	}
+	if len(query) > 0 {
+		p += "?" + query.Encode()
+	}
 	return c.executeVMAction(action, p)
 }
 
@@ -350,22 +582,60 @@ func (c *Client) executeVMAction(action, actionPath string) (Task, error) {
 	return task, nil
 }
 
+// TaskWaitOptions configures how long to wait for a director task and how
+// often to poll it
+type TaskWaitOptions struct {
+	// Timeout is how long to wait for the task, each operation has its own default
+	Timeout time.Duration
+	// PollInterval is how often the task is polled, defaults to 1 second
+	PollInterval time.Duration
+}
+
+func (o TaskWaitOptions) timeout(defaultTimeout time.Duration) time.Duration {
+	if o.Timeout <= 0 {
+		return defaultTimeout
+	}
+	return o.Timeout
+}
+
+func (o TaskWaitOptions) pollInterval() time.Duration {
+	if o.PollInterval <= 0 {
+		return time.Second
+	}
+	return o.PollInterval
+}
+
+// WaitUntilDone polls the task every second until it is done, fails or the
+// timeout expires
 func (c *Client) WaitUntilDone(task Task, timeout time.Duration) (Task, error) {
+	return c.WaitUntilDoneWithInterval(task, timeout, time.Second)
+}
+
+// WaitUntilDoneWithInterval polls the task at the given interval until it is
+// done, fails or the timeout expires
+func (c *Client) WaitUntilDoneWithInterval(task Task, timeout, interval time.Duration) (Task, error) {
 	type Result struct {
 		Task  Task
 		Error error
 	}
-	doneCh := make(chan Result)
-	ticker := time.NewTicker(time.Second)
+	doneCh := make(chan Result, 1)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	go func(taskID int) {
-		for range ticker.C {
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+			}
 			curTask, err := c.GetTask(taskID)
 			if err != nil {
 				doneCh <- Result{
 					Task:  Task{},
-					Error: fmt.Errorf("error getting task %d status: %w", curTask.ID, err),
+					Error: fmt.Errorf("error getting task %d status: %w", taskID, err),
 				}
 				return
 			}
@@ -385,12 +655,10 @@ func (c *Client) WaitUntilDone(task Task, timeout time.Duration) (Task, error) {
 		}
 	}(task.ID)
 
-	for {
-		select {
-		case result := <-doneCh:
-			return result.Task, result.Error
-		case <-time.After(timeout):
-			return task, fmt.Errorf("timed out waiting for task %d to complete", task.ID)
-		}
+	select {
+	case result := <-doneCh:
+		return result.Task, result.Error
+	case <-time.After(timeout):
+		return task, fmt.Errorf("timed out waiting for task %d to complete", task.ID)
 	}
 }
@@ -5,6 +5,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"net/url"
+	"time"
 )
 
 var _ = Describe("Api", func() {
@@ -65,6 +66,107 @@ var _ = Describe("Api", func() {
 			})
 		})
 
+		Describe("Test delete stemcells", func() {
+			BeforeEach(func() {
+				setupMockRoutes([]MockRoute{
+					{"GET", "/stemcells", cpiStemcells, ""},
+					{"DELETE", "/stemcells/:name/:version", "", "/tasks/2"},
+					{"GET", "/tasks/2", task, ""},
+				}, "basic")
+				config := &Config{
+					BOSHAddress: server.URL,
+					Username:    "admin",
+					Password:    "admin",
+				}
+
+				client, _ = NewClient(config)
+			})
+
+			AfterEach(func() {
+				teardown()
+			})
+
+			It("can delete an unused stemcell", func() {
+				task, err := client.DeleteStemcell("bosh-vsphere-esxi-windows2019-go_agent", "2019.70", false)
+				Expect(err).Should(BeNil())
+				Expect(task.ID).Should(Equal(2))
+				Expect(receivedRequests).Should(HaveKey("DELETE /stemcells/bosh-vsphere-esxi-windows2019-go_agent/2019.70"))
+			})
+
+			It("refuses to delete a stemcell in use", func() {
+				_, err := client.DeleteStemcell("bosh-vsphere-esxi-ubuntu-jammy-go_agent", "1.92", false)
+				Expect(err).Should(MatchError(ContainSubstring("used by deployments cf")))
+				Expect(receivedRequests).ShouldNot(HaveKey("DELETE /stemcells/bosh-vsphere-esxi-ubuntu-jammy-go_agent/1.92"))
+			})
+
+			It("refuses to delete a missing stemcell", func() {
+				_, err := client.DeleteStemcell("bosh-vsphere-esxi-ubuntu-jammy-go_agent", "1.80", false)
+				Expect(err).Should(MatchError(ContainSubstring("not found")))
+			})
+
+			It("can force the deletion of a stemcell in use", func() {
+				task, err := client.DeleteStemcell("bosh-vsphere-esxi-ubuntu-jammy-go_agent", "1.92", true)
+				Expect(err).Should(BeNil())
+				Expect(task.ID).Should(Equal(2))
+				Expect(receivedRequests["DELETE /stemcells/bosh-vsphere-esxi-ubuntu-jammy-go_agent/1.92"].Query).Should(HaveKeyWithValue("force", []string{"true"}))
+			})
+		})
+
+		Describe("Test delete releases", func() {
+			BeforeEach(func() {
+				setupMockRoutes([]MockRoute{
+					{"GET", "/releases", releaseVersions, ""},
+					{"DELETE", "/releases/nginx", "", "/tasks/2"},
+					{"GET", "/tasks/2", task, ""},
+				}, "basic")
+				config := &Config{
+					BOSHAddress: server.URL,
+					Username:    "admin",
+					Password:    "admin",
+				}
+
+				client, _ = NewClient(config)
+			})
+
+			AfterEach(func() {
+				teardown()
+			})
+
+			It("can delete a release version that is not deployed", func() {
+				task, err := client.DeleteRelease("nginx", "1.21.5", false)
+				Expect(err).Should(BeNil())
+				Expect(task.ID).Should(Equal(2))
+				query := receivedRequests["DELETE /releases/nginx"].Query
+				Expect(query).Should(HaveKeyWithValue("version", []string{"1.21.5"}))
+				Expect(query).ShouldNot(HaveKey("force"))
+			})
+
+			It("refuses to delete a deployed release version", func() {
+				_, err := client.DeleteRelease("nginx", "1.21.6", false)
+				Expect(err).Should(MatchError(ContainSubstring("nginx/1.21.6 is currently deployed")))
+				Expect(receivedRequests).ShouldNot(HaveKey("DELETE /releases/nginx"))
+			})
+
+			It("refuses to delete all versions of a release when one is deployed", func() {
+				_, err := client.DeleteRelease("nginx", "", false)
+				Expect(err).Should(HaveOccurred())
+				Expect(receivedRequests).ShouldNot(HaveKey("DELETE /releases/nginx"))
+			})
+
+			It("refuses to delete a missing release", func() {
+				_, err := client.DeleteRelease("haproxy", "", false)
+				Expect(err).Should(MatchError(ContainSubstring("release haproxy not found")))
+			})
+
+			It("can force the deletion of every version of a release", func() {
+				_, err := client.DeleteRelease("nginx", "", true)
+				Expect(err).Should(BeNil())
+				query := receivedRequests["DELETE /releases/nginx"].Query
+				Expect(query).ShouldNot(HaveKey("version"))
+				Expect(query).Should(HaveKeyWithValue("force", []string{"true"}))
+			})
+		})
+
 		Describe("Test deployments", func() {
 			Describe("get deployments", func() {
 				BeforeEach(func() {
@@ -253,6 +355,64 @@ var _ = Describe("Api", func() {
 			})
 		})
 
+		Describe("Test get deployment vms with options", func() {
+			AfterEach(func() {
+				teardown()
+			})
+
+			It("can get basic deployment vms without a task", func() {
+				setupMockRoute(MockRoute{"GET", "/deployments/foo/vms", basicVMs, ""}, "basic")
+				config := &Config{
+					BOSHAddress: server.URL,
+					Username:    "admin",
+					Password:    "admin",
+				}
+				client, _ = NewClient(config)
+
+				vms, err := client.GetDeploymentVMsWithOptions("foo", VMsOptions{})
+				Expect(err).Should(BeNil())
+				Expect(vms).Should(HaveLen(2))
+				Expect(vms[0].VMCID).Should(Equal("ec974048-3352-4ba4-669d-beab87b16bcb"))
+				Expect(vms[0].AgentID).Should(Equal("c5e7c705-459e-41c0-b640-db32d8dc6e71"))
+				Expect(vms[0].JobName).Should(Equal("doppler_z1"))
+				Expect(vms[0].ID).Should(Equal("4a9278c8-e93a-4d6a-b22c-13560208da9e"))
+				Expect(vms[0].IPs).Should(Equal([]string{"10.244.0.142"}))
+				Expect(vms[0].AZ).Should(Equal("z1"))
+				Expect(vms[1].Index).Should(Equal(1))
+			})
+
+			It("can stream full deployment vms", func() {
+				setupMockRoutes([]MockRoute{
+					{"GET", "/deployments/foo/vms", "", "/tasks/2"},
+					{"GET", "/tasks/2", task, ""},
+					{"GET", "/tasks/2/output", vms + "\n" + vms + "\n", ""},
+				}, "basic")
+				config := &Config{
+					BOSHAddress: server.URL,
+					Username:    "admin",
+					Password:    "admin",
+				}
+				client, _ = NewClient(config)
+
+				var cids []string
+				err := client.StreamDeploymentVMs("foo", VMsOptions{
+					Full: true,
+					TaskWaitOptions: TaskWaitOptions{
+						Timeout:      time.Second * 5,
+						PollInterval: time.Millisecond * 10,
+					},
+				}, func(vm VM) error {
+					cids = append(cids, vm.VMCID)
+					return nil
+				})
+				Expect(err).Should(BeNil())
+				Expect(cids).Should(Equal([]string{
+					"ec974048-3352-4ba4-669d-beab87b16bcb",
+					"ec974048-3352-4ba4-669d-beab87b16bcb",
+				}))
+			})
+		})
+
 		Describe("Test stop instance", func() {
 			BeforeEach(func() {
 				setupMockRoutes([]MockRoute{
@@ -306,6 +466,115 @@ var _ = Describe("Api", func() {
 				task, err := client.StopNoConverge("deployment-foo", "job-foo", "id-foo")
 				Expect(err).Should(BeNil())
 				Expect(task.State).Should(Equal("done"))
+				Expect(receivedRequests["PUT /deployments/deployment-foo/instance_groups/job-foo/id-foo/actions/stopped"].Query).Should(BeEmpty())
+			})
+
+			It("can hard stop an instance without draining", func() {
+				_, err := client.ChangeInstanceStateNoConverge("deployment-foo", "job-foo", "id-foo", InstanceStateDetached, VMActionOptions{
+					SkipDrain:   true,
+					Fix:         true,
+					Canaries:    "1",
+					MaxInFlight: "1",
+				})
+				Expect(err).Should(BeNil())
+				Expect(receivedRequests["PUT /deployments/deployment-foo/instance_groups/job-foo/id-foo/actions/stopped"].Query).Should(Equal(map[string][]string{
+					"hard":       {"true"},
+					"skip_drain": {"true"},
+					"fix":        {"true"},
+				}))
+			})
+		})
+
+		Describe("Test recreate instance group", func() {
+			BeforeEach(func() {
+				setupMockRoutes([]MockRoute{
+					{"PUT", "/deployments/deployment-foo/jobs/job-foo", "", "/tasks/3"},
+					{"GET", "/tasks/3", task3, ""},
+				}, "basic")
+
+				config := &Config{
+					BOSHAddress: server.URL,
+					Username:    "admin",
+					Password:    "admin",
+				}
+
+				client, _ = NewClient(config)
+			})
+
+			AfterEach(func() {
+				teardown()
+			})
+
+			It("can recreate all instances of a group", func() {
+				task, err := client.ChangeInstanceState("deployment-foo", "job-foo", "", InstanceStateRecreate, VMActionOptions{
+					SkipDrain:   true,
+					Fix:         true,
+					Canaries:    "1",
+					MaxInFlight: "25%",
+				})
+				Expect(err).Should(BeNil())
+				Expect(task.ID).Should(Equal(3))
+				Expect(receivedRequests["PUT /deployments/deployment-foo/jobs/job-foo"].Query).Should(Equal(map[string][]string{
+					"state":         {"recreate"},
+					"skip_drain":    {"true"},
+					"fix":           {"true"},
+					"canaries":      {"1"},
+					"max_in_flight": {"25%"},
+				}))
+			})
+
+			It("only sends the options that are set", func() {
+				_, err := client.ChangeInstanceState("deployment-foo", "job-foo", "", InstanceStateRecreate, VMActionOptions{Canaries: "2"})
+				Expect(err).Should(BeNil())
+				Expect(receivedRequests["PUT /deployments/deployment-foo/jobs/job-foo"].Query).Should(Equal(map[string][]string{
+					"state":    {"recreate"},
+					"canaries": {"2"},
+				}))
+			})
+		})
+
+		Describe("Test hard stop deployment", func() {
+			BeforeEach(func() {
+				setupMockRoutes([]MockRoute{
+					{"PUT", `/deployments/deployment-foo/jobs/\*`, "", "/tasks/3"},
+					{"GET", "/tasks/3", task3, ""},
+				}, "basic")
+
+				config := &Config{
+					BOSHAddress: server.URL,
+					Username:    "admin",
+					Password:    "admin",
+				}
+
+				client, _ = NewClient(config)
+			})
+
+			AfterEach(func() {
+				teardown()
+			})
+
+			It("can hard stop every instance of a deployment", func() {
+				task, err := client.ChangeInstanceState("deployment-foo", "", "", InstanceStateDetached, VMActionOptions{})
+				Expect(err).Should(BeNil())
+				Expect(task.ID).Should(Equal(3))
+				Expect(receivedRequests["PUT /deployments/deployment-foo/jobs/*"].Query).Should(Equal(map[string][]string{
+					"state": {"detached"},
+				}))
+			})
+
+			It("can hard stop without draining", func() {
+				_, err := client.ChangeInstanceState("deployment-foo", "", "", InstanceStateDetached, VMActionOptions{SkipDrain: true, MaxInFlight: "1"})
+				Expect(err).Should(BeNil())
+				Expect(receivedRequests["PUT /deployments/deployment-foo/jobs/*"].Query).Should(Equal(map[string][]string{
+					"state":         {"detached"},
+					"skip_drain":    {"true"},
+					"max_in_flight": {"1"},
+				}))
+			})
+
+			It("requires an instance without converge", func() {
+				_, err := client.ChangeInstanceStateNoConverge("deployment-foo", "job-foo", "", InstanceStateRecreate, VMActionOptions{})
+				Expect(err).Should(HaveOccurred())
 			})
 		})
 
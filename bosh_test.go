@@ -1,6 +1,8 @@
 package gogobosh_test
 
 import (
+	"bytes"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -14,8 +16,17 @@ var (
 	mux           *http.ServeMux
 	server        *httptest.Server
 	fakeUAAServer *httptest.Server
+
+	// receivedRequests holds the last request made to each "METHOD path" of the mock server
+	receivedRequests map[string]ReceivedRequest
 )
 
+type ReceivedRequest struct {
+	Query  map[string][]string
+	Header http.Header
+	Body   string
+}
+
 type MockRoute struct {
 	Method   string
 	Endpoint string
@@ -35,8 +46,10 @@ func setupMockRoutes(mockEndpoints []MockRoute, authType string) {
 	mux = http.NewServeMux()
 	server = httptest.NewServer(mux)
 	fakeUAAServer = FakeUAAServer()
+	receivedRequests = map[string]ReceivedRequest{}
 	m := martini.New()
 	m.Use(render.Renderer())
+	m.Use(recordRequest)
 	r := martini.NewRouter()
 	for _, mock := range mockEndpoints {
 		method := mock.Method
@@ -54,13 +67,25 @@ func setupMockRoutes(mockEndpoints []MockRoute, authType string) {
 				})
 			}
 		} else if method == "POST" {
-			r.Post(endpoint, func() string {
-				return output
-			})
+			if redirect != "" {
+				r.Post(endpoint, func(r render.Render) {
+					r.Redirect(redirect)
+				})
+			} else {
+				r.Post(endpoint, func() string {
+					return output
+				})
+			}
 		} else if method == "DELETE" {
-			r.Delete(endpoint, func() (int, string) {
-				return 204, output
-			})
+			if redirect != "" {
+				r.Delete(endpoint, func(r render.Render) {
+					r.Redirect(redirect)
+				})
+			} else {
+				r.Delete(endpoint, func() (int, string) {
+					return 204, output
+				})
+			}
 		} else if method == "PUT" {
 			if redirect != "" {
 				r.Put(endpoint, func(r render.Render) {
@@ -108,6 +133,16 @@ func setupMockRoutes(mockEndpoints []MockRoute, authType string) {
 	mux.Handle("/", m)
 }
 
+func recordRequest(req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	receivedRequests[req.Method+" "+req.URL.Path] = ReceivedRequest{
+		Query:  req.URL.Query(),
+		Header: req.Header,
+		Body:   string(body),
+	}
+}
+
 func FakeUAAServer() *httptest.Server {
 	mux := http.NewServeMux()
 	server := httptest.NewServer(mux)
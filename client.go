@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	boshhttp "github.com/cloudfoundry/bosh-utils/httpclient"
@@ -21,6 +22,11 @@ import (
 type Client struct {
 	config   Config
 	Endpoint Endpoint
+
+	// deletedOrphansLock guards deletedOrphans, the orphaned VMs deleted by
+	// DeleteOrphanedVMs that the director keeps listing until its next cleanup
+	deletedOrphansLock sync.Mutex
+	deletedOrphans     map[string]bool
 }
 
 // Config is used to configure the creation of a client
@@ -49,6 +55,9 @@ type request struct {
 	params url.Values
 	body   io.Reader
 	obj    interface{}
+	ctx    context.Context
+	// contentLength of body when it is a stream of known length
+	contentLength int64
 }
 
 // DefaultConfig configuration for client
@@ -251,12 +260,25 @@ func (c *Client) DoRequest(r *request) (*http.Response, error) {
 			}
 			resp, err = c.config.HttpClient.Do(req)
 		} else {
-			return nil, fmt.Errorf("http %s request to %s failed with %s", req.Method, req.URL, resp.Status)
+			_ = resp.Body.Close()
+			return nil, &statusError{method: req.Method, url: req.URL.String(), statusCode: resp.StatusCode, status: resp.Status}
 		}
 	}
 	return resp, err
 }
 
+// statusError is returned by DoRequest when the director answers with an error status
+type statusError struct {
+	method     string
+	url        string
+	statusCode int
+	status     string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("http %s request to %s failed with %s", e.method, e.url, e.status)
+}
+
 // GetUUID returns the BOSH UUID
 func (c *Client) GetUUID() (string, error) {
 	info, err := c.GetInfo()
@@ -346,7 +368,18 @@ func (r *request) toHTTP() (*http.Request, error) {
 	}
 
 	// Create the HTTP request
-	return http.NewRequest(r.method, r.url, r.body)
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, r.url, r.body)
+	if err != nil {
+		return nil, err
+	}
+	if r.contentLength > 0 {
+		req.ContentLength = r.contentLength
+	}
+	return req, nil
 }
 
 // GetToken - returns the current token bearer
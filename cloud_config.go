@@ -0,0 +1,303 @@
+package gogobosh
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Network types of a cloud config
+const (
+	NetworkTypeManual  = "manual"
+	NetworkTypeDynamic = "dynamic"
+	NetworkTypeVIP     = "vip"
+)
+
+// CloudConfig is the typed form of a cloud config
+type CloudConfig struct {
+	AZs          []CloudConfigAZ          `yaml:"azs,omitempty"`
+	Networks     []CloudConfigNetwork     `yaml:"networks,omitempty"`
+	VMTypes      []CloudConfigVMType      `yaml:"vm_types,omitempty"`
+	VMExtensions []CloudConfigVMExtension `yaml:"vm_extensions,omitempty"`
+	DiskTypes    []CloudConfigDiskType    `yaml:"disk_types,omitempty"`
+	Compilation  *CloudConfigCompilation  `yaml:"compilation,omitempty"`
+
+	// Extra keeps the keys that are not modeled, so that a parsed cloud config
+	// can be updated without losing them. The nested types keep theirs the same way.
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// CloudConfigAZ is an availability zone
+type CloudConfigAZ struct {
+	Name            string                 `yaml:"name"`
+	CPI             string                 `yaml:"cpi,omitempty"`
+	CloudProperties map[string]interface{} `yaml:"cloud_properties,omitempty"`
+	Extra           map[string]interface{} `yaml:",inline"`
+}
+
+// CloudConfigNetwork is a manual, dynamic or vip network. The type defaults to manual.
+type CloudConfigNetwork struct {
+	Name            string                 `yaml:"name"`
+	Type            string                 `yaml:"type,omitempty"`
+	Subnets         []CloudConfigSubnet    `yaml:"subnets,omitempty"`
+	CloudProperties map[string]interface{} `yaml:"cloud_properties,omitempty"`
+	Extra           map[string]interface{} `yaml:",inline"`
+}
+
+// CloudConfigSubnet is a subnet of a network. Reserved and static entries are
+// either single IPs or ranges like "10.0.0.10 - 10.0.0.20".
+type CloudConfigSubnet struct {
+	Range           string                 `yaml:"range,omitempty"`
+	Gateway         string                 `yaml:"gateway,omitempty"`
+	DNS             []string               `yaml:"dns,omitempty"`
+	Reserved        []string               `yaml:"reserved,omitempty"`
+	Static          []string               `yaml:"static,omitempty"`
+	AZ              string                 `yaml:"az,omitempty"`
+	AZs             []string               `yaml:"azs,omitempty"`
+	CloudProperties map[string]interface{} `yaml:"cloud_properties,omitempty"`
+	Extra           map[string]interface{} `yaml:",inline"`
+}
+
+// CloudConfigVMType is a named set of VM cloud properties
+type CloudConfigVMType struct {
+	Name            string                 `yaml:"name"`
+	CloudProperties map[string]interface{} `yaml:"cloud_properties,omitempty"`
+	Extra           map[string]interface{} `yaml:",inline"`
+}
+
+// CloudConfigVMExtension is a named set of cloud properties merged into VMs using it
+type CloudConfigVMExtension struct {
+	Name            string                 `yaml:"name"`
+	CloudProperties map[string]interface{} `yaml:"cloud_properties,omitempty"`
+	Extra           map[string]interface{} `yaml:",inline"`
+}
+
+// CloudConfigDiskType is a named persistent disk size and cloud properties
+type CloudConfigDiskType struct {
+	Name            string                 `yaml:"name"`
+	DiskSize        int                    `yaml:"disk_size"`
+	CloudProperties map[string]interface{} `yaml:"cloud_properties,omitempty"`
+	Extra           map[string]interface{} `yaml:",inline"`
+}
+
+// CloudConfigCompilation configures the VMs used to compile packages
+type CloudConfigCompilation struct {
+	Workers             int                    `yaml:"workers"`
+	ReuseCompilationVMs bool                   `yaml:"reuse_compilation_vms,omitempty"`
+	AZ                  string                 `yaml:"az,omitempty"`
+	VMType              string                 `yaml:"vm_type,omitempty"`
+	Network             string                 `yaml:"network"`
+	CloudProperties     map[string]interface{} `yaml:"cloud_properties,omitempty"`
+	Extra               map[string]interface{} `yaml:",inline"`
+}
+
+// ParseCloudConfig parses the YAML content of a cloud config
+func ParseCloudConfig(content string) (CloudConfig, error) {
+	var cc CloudConfig
+	err := yaml.Unmarshal([]byte(content), &cc)
+	if err != nil {
+		return CloudConfig{}, fmt.Errorf("error parsing cloud config: %w", err)
+	}
+	return cc, nil
+}
+
+// Marshal renders the cloud config as YAML
+func (cc CloudConfig) Marshal() (string, error) {
+	b, err := yaml.Marshal(&cc)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling cloud config: %w", err)
+	}
+	return string(b), nil
+}
+
+// Validate catches the mistakes the director would only report on upload or
+// deploy: duplicate names, undefined AZs, VM types and networks, overlapping
+// subnets and reserved, static or gateway IPs outside of their subnet.
+// Every problem found is returned, joined into a single error.
+func (cc CloudConfig) Validate() error {
+	var errs []error
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	azs := map[string]bool{}
+	for _, az := range cc.AZs {
+		if azs[az.Name] {
+			add("duplicate az %q", az.Name)
+		}
+		azs[az.Name] = true
+	}
+	checkAZ := func(where, az string) {
+		if !azs[az] {
+			add("%s references undefined az %q", where, az)
+		}
+	}
+
+	vmTypes := uniqueNames("vm_type", len(cc.VMTypes), func(i int) string { return cc.VMTypes[i].Name }, add)
+	uniqueNames("vm_extension", len(cc.VMExtensions), func(i int) string { return cc.VMExtensions[i].Name }, add)
+	uniqueNames("disk_type", len(cc.DiskTypes), func(i int) string { return cc.DiskTypes[i].Name }, add)
+	networks := uniqueNames("network", len(cc.Networks), func(i int) string { return cc.Networks[i].Name }, add)
+
+	type namedPrefix struct {
+		name   string
+		prefix netip.Prefix
+	}
+	var ranges []namedPrefix
+	for _, network := range cc.Networks {
+		manual := network.Type == "" || network.Type == NetworkTypeManual
+		if !manual && network.Type != NetworkTypeDynamic && network.Type != NetworkTypeVIP {
+			add("network %q has unknown type %q", network.Name, network.Type)
+			continue
+		}
+
+		for i, subnet := range network.Subnets {
+			where := fmt.Sprintf("network %q subnet %d", network.Name, i)
+			if subnet.AZ != "" {
+				checkAZ(where, subnet.AZ)
+			}
+			for _, az := range subnet.AZs {
+				checkAZ(where, az)
+			}
+			if !manual {
+				continue
+			}
+
+			prefix, err := netip.ParsePrefix(subnet.Range)
+			if err != nil {
+				add("%s has invalid range %q", where, subnet.Range)
+				continue
+			}
+			prefix = prefix.Masked()
+			for _, other := range ranges {
+				if other.prefix.Overlaps(prefix) {
+					add("%s range %s overlaps %s range %s", where, prefix, other.name, other.prefix)
+				}
+			}
+			ranges = append(ranges, namedPrefix{name: where, prefix: prefix})
+
+			if subnet.Gateway != "" {
+				gateway, err := netip.ParseAddr(subnet.Gateway)
+				if err != nil || !prefix.Contains(gateway) {
+					add("%s gateway %q is not in range %s", where, subnet.Gateway, prefix)
+				}
+			}
+
+			reserved := parseIPRanges(where, "reserved", subnet.Reserved, prefix, add)
+			static := parseIPRanges(where, "static", subnet.Static, prefix, add)
+			for _, s := range static {
+				for _, r := range reserved {
+					if s.overlaps(r) {
+						add("%s static IPs %s overlap reserved IPs %s", where, s, r)
+					}
+				}
+			}
+		}
+	}
+
+	if cc.Compilation != nil {
+		if cc.Compilation.AZ != "" {
+			checkAZ("compilation", cc.Compilation.AZ)
+		}
+		if cc.Compilation.VMType != "" && !vmTypes[cc.Compilation.VMType] {
+			add("compilation references undefined vm_type %q", cc.Compilation.VMType)
+		}
+		if !networks[cc.Compilation.Network] {
+			add("compilation references undefined network %q", cc.Compilation.Network)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func uniqueNames(kind string, n int, name func(int) string, add func(string, ...interface{})) map[string]bool {
+	seen := map[string]bool{}
+	for i := 0; i < n; i++ {
+		if seen[name(i)] {
+			add("duplicate %s %q", kind, name(i))
+		}
+		seen[name(i)] = true
+	}
+	return seen
+}
+
+// ipRange is an inclusive range of IP addresses
+type ipRange struct {
+	first netip.Addr
+	last  netip.Addr
+}
+
+func (r ipRange) overlaps(other ipRange) bool {
+	return r.first.Compare(other.last) <= 0 && other.first.Compare(r.last) <= 0
+}
+
+func (r ipRange) String() string {
+	if r.first == r.last {
+		return r.first.String()
+	}
+	return r.first.String() + " - " + r.last.String()
+}
+
+// parseIPRange parses a single IP or a range like "10.0.0.10 - 10.0.0.20"
+func parseIPRange(s string) (ipRange, error) {
+	parts := strings.SplitN(s, "-", 2)
+	first, err := netip.ParseAddr(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return ipRange{}, err
+	}
+	last := first
+	if len(parts) == 2 {
+		last, err = netip.ParseAddr(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return ipRange{}, err
+		}
+	}
+	if last.Less(first) {
+		return ipRange{}, fmt.Errorf("range %q ends before it starts", s)
+	}
+	return ipRange{first: first, last: last}, nil
+}
+
+func parseIPRanges(where, kind string, entries []string, prefix netip.Prefix, add func(string, ...interface{})) []ipRange {
+	var ranges []ipRange
+	for _, entry := range entries {
+		r, err := parseIPRange(entry)
+		if err != nil {
+			add("%s has invalid %s IPs %q", where, kind, entry)
+			continue
+		}
+		if !prefix.Contains(r.first) || !prefix.Contains(r.last) {
+			add("%s %s IPs %s are not in range %s", where, kind, r, prefix)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// GetTypedCloudConfig returns the current default cloud config parsed into a CloudConfig
+func (c *Client) GetTypedCloudConfig() (CloudConfig, error) {
+	cfgs, err := c.GetConfigs(ConfigsFilter{Type: ConfigTypeCloud, Name: "default", Latest: true})
+	if err != nil {
+		return CloudConfig{}, err
+	}
+	if len(cfgs) == 0 {
+		return CloudConfig{}, nil
+	}
+	return ParseCloudConfig(cfgs[0].Content)
+}
+
+// UpdateTypedCloudConfig validates the cloud config and uploads it as the
+// default cloud config
+func (c *Client) UpdateTypedCloudConfig(cc CloudConfig) error {
+	err := cc.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid cloud config: %w", err)
+	}
+	content, err := cc.Marshal()
+	if err != nil {
+		return err
+	}
+	return c.UpdateCloudConfig(content)
+}
@@ -0,0 +1,119 @@
+package gogobosh_test
+
+import (
+	"encoding/json"
+
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CloudConfig", func() {
+	Describe("Test typed cloud config", func() {
+		It("can parse a cloud config", func() {
+			cc, err := ParseCloudConfig(typedCloudConfig)
+			Expect(err).Should(BeNil())
+			Expect(cc.AZs).Should(HaveLen(2))
+			Expect(cc.AZs[0].CloudProperties).Should(HaveKeyWithValue("zone", "us-east-1a"))
+			Expect(cc.VMTypes[0].Name).Should(Equal("default"))
+			Expect(cc.VMExtensions[0].Name).Should(Equal("public-lb"))
+			Expect(cc.DiskTypes[0].DiskSize).Should(Equal(3072))
+			Expect(cc.Networks[0].Type).Should(Equal(NetworkTypeManual))
+			Expect(cc.Networks[0].Subnets[0].Range).Should(Equal("10.244.0.0/24"))
+			Expect(cc.Networks[0].Subnets[0].Static).Should(Equal([]string{"10.244.0.34", "10.244.0.40 - 10.244.0.49"}))
+			Expect(cc.Networks[0].Subnets[1].AZs).Should(Equal([]string{"z2"}))
+			Expect(cc.Networks[1].Type).Should(Equal(NetworkTypeVIP))
+			Expect(cc.Compilation.Workers).Should(Equal(5))
+			Expect(cc.Compilation.ReuseCompilationVMs).Should(BeTrue())
+			Expect(cc.Compilation.Network).Should(Equal("default"))
+		})
+
+		It("round trips through YAML", func() {
+			cc, err := ParseCloudConfig(typedCloudConfig)
+			Expect(err).Should(BeNil())
+			content, err := cc.Marshal()
+			Expect(err).Should(BeNil())
+			Expect(content).Should(MatchYAML(typedCloudConfig))
+		})
+
+		It("keeps the keys it does not model", func() {
+			cc, err := ParseCloudConfig(extendedCloudConfig)
+			Expect(err).Should(BeNil())
+			Expect(cc.Networks[0].Extra).Should(HaveKeyWithValue("managed", true))
+			Expect(cc.Networks[0].Subnets[0].Extra).Should(HaveKeyWithValue("name", "default-z1"))
+			Expect(cc.Compilation.Extra).Should(HaveKeyWithValue("orphan_workers", true))
+			Expect(cc.Compilation.Extra).Should(HaveKey("env"))
+			Expect(cc.Compilation.Extra).Should(HaveKey("vm_extensions"))
+
+			content, err := cc.Marshal()
+			Expect(err).Should(BeNil())
+			Expect(content).Should(MatchYAML(extendedCloudConfig))
+		})
+
+		It("accepts a valid cloud config", func() {
+			cc, err := ParseCloudConfig(typedCloudConfig)
+			Expect(err).Should(BeNil())
+			Expect(cc.Validate()).To(Succeed())
+		})
+
+		It("reports every problem of an invalid cloud config", func() {
+			cc, err := ParseCloudConfig(invalidCloudConfig)
+			Expect(err).Should(BeNil())
+			err = cc.Validate()
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring(`duplicate az "z1"`))
+			Expect(err.Error()).Should(ContainSubstring(`network "default" subnet 0 references undefined az "z9"`))
+			Expect(err.Error()).Should(ContainSubstring(`network "default" subnet 0 gateway "10.245.0.1" is not in range 10.244.0.0/24`))
+			Expect(err.Error()).Should(ContainSubstring(`network "default" subnet 0 static IPs 10.244.1.34 are not in range 10.244.0.0/24`))
+			Expect(err.Error()).Should(ContainSubstring(`network "default" subnet 0 static IPs 10.244.0.5 overlap reserved IPs 10.244.0.2 - 10.244.0.9`))
+			Expect(err.Error()).Should(ContainSubstring(`network "other" subnet 0 range 10.244.0.128/25 overlaps network "default" subnet 0 range 10.244.0.0/24`))
+			Expect(err.Error()).Should(ContainSubstring(`compilation references undefined vm_type "large"`))
+			Expect(err.Error()).Should(ContainSubstring(`compilation references undefined network "missing"`))
+		})
+	})
+
+	Describe("Test typed cloud config update", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoute(MockRoute{"POST", "/configs", config, ""}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("uploads a valid cloud config", func() {
+			cc, err := ParseCloudConfig(typedCloudConfig)
+			Expect(err).Should(BeNil())
+			Expect(client.UpdateTypedCloudConfig(cc)).To(Succeed())
+			Expect(receivedRequests["POST /configs"].Body).Should(ContainSubstring(`"type":"cloud"`))
+		})
+
+		It("uploads the keys it does not model", func() {
+			cc, err := ParseCloudConfig(extendedCloudConfig)
+			Expect(err).Should(BeNil())
+			Expect(client.UpdateTypedCloudConfig(cc)).To(Succeed())
+
+			var body struct {
+				Content string `json:"content"`
+			}
+			Expect(json.Unmarshal([]byte(receivedRequests["POST /configs"].Body), &body)).To(Succeed())
+			Expect(body.Content).Should(MatchYAML(extendedCloudConfig))
+		})
+
+		It("refuses to upload an invalid cloud config", func() {
+			cc, err := ParseCloudConfig(invalidCloudConfig)
+			Expect(err).Should(BeNil())
+			Expect(client.UpdateTypedCloudConfig(cc)).NotTo(Succeed())
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /configs"))
+		})
+	})
+})
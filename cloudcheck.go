@@ -0,0 +1,153 @@
+package gogobosh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ResolutionPolicy maps problem types to the names of the resolutions to
+// apply, in order of preference
+type ResolutionPolicy map[string][]string
+
+// DefaultResolutionPolicy recreates VMs that are missing or have an
+// unresponsive agent and reattaches mismatched disks. Problems that could
+// lose data, such as inactive or missing disks, are left for an operator.
+func DefaultResolutionPolicy() ResolutionPolicy {
+	return ResolutionPolicy{
+		"unresponsive_agent":  {"recreate_vm"},
+		"missing_vm":          {"recreate_vm"},
+		"mount_info_mismatch": {"reattach_disk"},
+	}
+}
+
+// Resolve picks the first preferred resolution offered by each problem. The
+// problems without an acceptable resolution are returned as unresolved.
+func (p ResolutionPolicy) Resolve(problems []Problem) (map[int]string, []Problem) {
+	resolutions := map[int]string{}
+	var unresolved []Problem
+	for _, problem := range problems {
+		resolution, ok := p.resolve(problem)
+		if !ok {
+			unresolved = append(unresolved, problem)
+			continue
+		}
+		resolutions[problem.ID] = resolution
+	}
+	return resolutions, unresolved
+}
+
+func (p ResolutionPolicy) resolve(problem Problem) (string, bool) {
+	for _, preferred := range p[problem.Type] {
+		for _, resolution := range problem.Resolutions {
+			if resolution.Name == preferred {
+				return preferred, true
+			}
+		}
+	}
+	return "", false
+}
+
+// CloudCheckOptions configures how long to wait for the cloud check tasks
+type CloudCheckOptions struct {
+	// TaskWaitOptions bounds the wait for each cloud check task, which defaults to 30 minutes
+	TaskWaitOptions
+}
+
+// CloudCheckReport describes what an unattended cloud check found and did
+type CloudCheckReport struct {
+	Problems    []Problem
+	Resolutions map[int]string
+	Unresolved  []Problem
+	Task        Task
+}
+
+// ScanForProblems starts a cloud check scan of the specified deployment
+func (c *Client) ScanForProblems(deployment string) (Task, error) {
+	r := c.NewRequest("POST", "/deployments/"+deployment+"/scans")
+	r.header["Content-Type"] = "application/json"
+
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error scanning deployment %s for problems: %w", deployment, err)
+	}
+	return task, nil
+}
+
+// GetProblems returns the problems found by the last scan of the specified deployment
+func (c *Client) GetProblems(deployment string) ([]Problem, error) {
+	r := c.NewRequest("GET", "/deployments/"+deployment+"/problems")
+	var problems []Problem
+	err := c.DoRequestAndUnmarshal(r, &problems)
+	if err != nil {
+		return []Problem{}, fmt.Errorf("error requesting deployment %s problems: %w", deployment, err)
+	}
+	return problems, nil
+}
+
+// ResolveProblems applies the resolutions, keyed by problem ID, to the
+// problems of the specified deployment
+func (c *Client) ResolveProblems(deployment string, resolutions map[int]string) (Task, error) {
+	r := c.NewRequest("PUT", "/deployments/"+deployment+"/problems")
+	in := struct {
+		Resolutions          map[string]string `json:"resolutions"`
+		MaxInFlightOverrides map[string]string `json:"max_in_flight_overrides"`
+	}{
+		Resolutions:          map[string]string{},
+		MaxInFlightOverrides: map[string]string{},
+	}
+	for id, resolution := range resolutions {
+		in.Resolutions[strconv.Itoa(id)] = resolution
+	}
+
+	b, err := json.Marshal(&in)
+	if err != nil {
+		return Task{}, fmt.Errorf("error marshalling the problem resolutions: %w", err)
+	}
+	r.body = bytes.NewBuffer(b)
+	r.header["Content-Type"] = "application/json"
+
+	var task Task
+	err = c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error resolving deployment %s problems: %w", deployment, err)
+	}
+	return task, nil
+}
+
+// CloudCheck scans the specified deployment, resolves the problems found
+// according to the policy and waits for the resolutions to be applied
+func (c *Client) CloudCheck(deployment string, policy ResolutionPolicy, opts CloudCheckOptions) (CloudCheckReport, error) {
+	task, err := c.ScanForProblems(deployment)
+	if err != nil {
+		return CloudCheckReport{}, err
+	}
+	_, err = c.WaitUntilDoneWithInterval(task, opts.timeout(time.Minute*30), opts.pollInterval())
+	if err != nil {
+		return CloudCheckReport{}, fmt.Errorf("error waiting for deployment %s scan to complete: %w", deployment, err)
+	}
+
+	problems, err := c.GetProblems(deployment)
+	if err != nil {
+		return CloudCheckReport{}, err
+	}
+
+	report := CloudCheckReport{Problems: problems}
+	report.Resolutions, report.Unresolved = policy.Resolve(problems)
+	if len(report.Resolutions) == 0 {
+		return report, nil
+	}
+
+	task, err = c.ResolveProblems(deployment, report.Resolutions)
+	if err != nil {
+		return report, err
+	}
+	report.Task, err = c.WaitUntilDoneWithInterval(task, opts.timeout(time.Minute*30), opts.pollInterval())
+	if err != nil {
+		return report, fmt.Errorf("error waiting for deployment %s problems to be resolved: %w", deployment, err)
+	}
+	return report, nil
+}
@@ -0,0 +1,88 @@
+package gogobosh_test
+
+import (
+	"time"
+
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CloudCheck", func() {
+	Describe("Test resolution policy", func() {
+		It("picks the first offered resolution in order of preference", func() {
+			policy := ResolutionPolicy{
+				"unresponsive_agent": {"recreate_vm_without_wait", "reboot_vm"},
+			}
+			resolutions, unresolved := policy.Resolve([]Problem{
+				{
+					ID:   1,
+					Type: "unresponsive_agent",
+					Resolutions: []ProblemResolution{
+						{Name: "ignore"},
+						{Name: "reboot_vm"},
+						{Name: "recreate_vm"},
+					},
+				},
+				{
+					ID:          2,
+					Type:        "missing_disk",
+					Resolutions: []ProblemResolution{{Name: "ignore"}},
+				},
+			})
+			Expect(resolutions).Should(Equal(map[int]string{1: "reboot_vm"}))
+			Expect(unresolved).Should(HaveLen(1))
+			Expect(unresolved[0].ID).Should(Equal(2))
+		})
+	})
+
+	Describe("Test cloud check", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"POST", "/deployments/cf-warden/scans", "", "/tasks/6"},
+				{"GET", "/deployments/cf-warden/problems", problems, ""},
+				{"PUT", "/deployments/cf-warden/problems", "", "/tasks/6"},
+				{"GET", "/tasks/6", cloudCheckTask, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("can list problems", func() {
+			problems, err := client.GetProblems("cf-warden")
+			Expect(err).Should(BeNil())
+			Expect(problems).Should(HaveLen(2))
+			Expect(problems[0].ID).Should(Equal(4))
+			Expect(problems[0].Type).Should(Equal("unresponsive_agent"))
+			Expect(problems[0].Resolutions[2].Name).Should(Equal("recreate_vm"))
+			Expect(problems[0].Resolutions[2].Plan).Should(Equal("Recreate VM and wait for processes to start"))
+			Expect(problems[1].Data["disk_id"]).Should(Equal(float64(7)))
+		})
+
+		It("can scan and resolve problems unattended", func() {
+			report, err := client.CloudCheck("cf-warden", DefaultResolutionPolicy(), CloudCheckOptions{
+				TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10},
+			})
+			Expect(err).Should(BeNil())
+			Expect(report.Problems).Should(HaveLen(2))
+			Expect(report.Resolutions).Should(Equal(map[int]string{4: "recreate_vm"}))
+			Expect(report.Unresolved).Should(HaveLen(1))
+			Expect(report.Unresolved[0].Type).Should(Equal("inactive_disk"))
+			Expect(report.Task.ID).Should(Equal(6))
+
+			req := receivedRequests["PUT /deployments/cf-warden/problems"]
+			Expect(req.Body).Should(MatchJSON(`{"resolutions": {"4": "recreate_vm"}, "max_in_flight_overrides": {}}`))
+		})
+	})
+})
@@ -0,0 +1,241 @@
+package gogobosh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Config types known to the director, any other type is a custom config
+const (
+	ConfigTypeCloud        = "cloud"
+	ConfigTypeRuntime      = "runtime"
+	ConfigTypeCPI          = "cpi"
+	ConfigTypeResurrection = "resurrection"
+)
+
+// ConfigsFilter narrows down the configs returned by GetConfigs
+type ConfigsFilter struct {
+	// Type only returns configs of the given type when set
+	Type string
+	// Name only returns configs with the given name when set
+	Name string
+	// Latest only returns the current version of each config instead of its whole history
+	Latest bool
+}
+
+// GetConfigs returns the configs matching the filter
+func (c *Client) GetConfigs(filter ConfigsFilter) ([]Cfg, error) {
+	query := url.Values{}
+	query.Set("latest", strconv.FormatBool(filter.Latest))
+	if filter.Type != "" {
+		query.Set("type", filter.Type)
+	}
+	if filter.Name != "" {
+		query.Set("name", filter.Name)
+	}
+
+	r := c.NewRequest("GET", "/configs?"+query.Encode())
+	var cfg []Cfg
+	err := c.DoRequestAndUnmarshal(r, &cfg)
+	if err != nil {
+		return []Cfg{}, fmt.Errorf("error requesting configs: %w", err)
+	}
+	return cfg, nil
+}
+
+// GetConfig returns the config version with the given ID
+func (c *Client) GetConfig(id string) (Cfg, error) {
+	r := c.NewRequest("GET", "/configs/"+id)
+	var cfg Cfg
+	err := c.DoRequestAndUnmarshal(r, &cfg)
+	if err != nil {
+		return Cfg{}, fmt.Errorf("error requesting config %s: %w", id, err)
+	}
+	return cfg, nil
+}
+
+// UpdateConfig creates a new version of the config with the given type and name
+func (c *Client) UpdateConfig(typ, name, content string) (Cfg, error) {
+	r := c.NewRequest("POST", "/configs")
+	in := struct {
+		Name    string `json:"name"`
+		Type    string `json:"type"`
+		Content string `json:"content"`
+	}{
+		Name:    name,
+		Type:    typ,
+		Content: content,
+	}
+	b, err := json.Marshal(&in)
+	if err != nil {
+		return Cfg{}, fmt.Errorf("error marshalling the %s config update: %w", typ, err)
+	}
+	r.body = bytes.NewBuffer(b)
+	r.header["Content-Type"] = "application/json"
+
+	var cfg Cfg
+	err = c.DoRequestAndUnmarshal(r, &cfg)
+	if err != nil {
+		return Cfg{}, fmt.Errorf("error updating the %s config %s: %w", typ, name, err)
+	}
+	return cfg, nil
+}
+
+// DeleteConfig deletes the config with the given type and name
+func (c *Client) DeleteConfig(typ, name string) error {
+	query := url.Values{}
+	query.Set("type", typ)
+	query.Set("name", name)
+
+	r := c.NewRequest("DELETE", "/configs?"+query.Encode())
+	resp, err := c.DoRequest(r)
+	if err != nil {
+		return fmt.Errorf("error deleting the %s config %s: %w", typ, name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	return nil
+}
+
+// DeleteConfigByID deletes the config version with the given ID
+func (c *Client) DeleteConfigByID(id string) error {
+	r := c.NewRequest("DELETE", "/configs/"+id)
+	resp, err := c.DoRequest(r)
+	if err != nil {
+		return fmt.Errorf("error deleting config %s: %w", id, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	return nil
+}
+
+// UnmarshalJSON decodes the [text, state] pair the director uses for diff lines
+func (l *ConfigDiffLine) UnmarshalJSON(b []byte) error {
+	var pair []*string
+	if err := json.Unmarshal(b, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("expected a [text, state] diff line, got %s", string(b))
+	}
+	*l = ConfigDiffLine{}
+	if pair[0] != nil {
+		l.Text = *pair[0]
+	}
+	if pair[1] != nil {
+		l.State = *pair[1]
+	}
+	return nil
+}
+
+// IsEmpty reports whether the diff has no added or removed lines
+func (d ConfigDiff) IsEmpty() bool {
+	for _, line := range d.Lines {
+		if line.State != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the diff with a +/- prefix on the changed lines
+func (d ConfigDiff) String() string {
+	var b strings.Builder
+	for _, line := range d.Lines {
+		switch line.State {
+		case "added":
+			b.WriteString("+ ")
+		case "removed":
+			b.WriteString("- ")
+		default:
+			b.WriteString("  ")
+		}
+		b.WriteString(line.Text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// DiffConfig returns the difference between the current config with the
+// given type and name and the content
+func (c *Client) DiffConfig(typ, name, content string) (ConfigDiff, error) {
+	in := struct {
+		Name    string `json:"name"`
+		Type    string `json:"type"`
+		Content string `json:"content"`
+	}{
+		Name:    name,
+		Type:    typ,
+		Content: content,
+	}
+	diff, err := c.diffConfig(&in)
+	if err != nil {
+		return ConfigDiff{}, fmt.Errorf("error diffing the %s config %s: %w", typ, name, err)
+	}
+	return diff, nil
+}
+
+// DiffConfigByID returns the difference between two config versions
+func (c *Client) DiffConfigByID(fromID, toID string) (ConfigDiff, error) {
+	type ref struct {
+		ID string `json:"id"`
+	}
+	in := struct {
+		From ref `json:"from"`
+		To   ref `json:"to"`
+	}{
+		From: ref{ID: fromID},
+		To:   ref{ID: toID},
+	}
+	diff, err := c.diffConfig(&in)
+	if err != nil {
+		return ConfigDiff{}, fmt.Errorf("error diffing configs %s and %s: %w", fromID, toID, err)
+	}
+	return diff, nil
+}
+
+func (c *Client) diffConfig(in interface{}) (ConfigDiff, error) {
+	r := c.NewRequest("POST", "/configs/diff")
+	b, err := json.Marshal(in)
+	if err != nil {
+		return ConfigDiff{}, err
+	}
+	r.body = bytes.NewBuffer(b)
+	r.header["Content-Type"] = "application/json"
+
+	var diff ConfigDiff
+	err = c.DoRequestAndUnmarshal(r, &diff)
+	if err != nil {
+		return ConfigDiff{}, err
+	}
+	return diff, nil
+}
+
+// UpdateConfigIfChanged only creates a new version of the config when the
+// content differs from the current one, so that reconcilers do not churn
+// config versions. It returns the current config and whether it was updated.
+func (c *Client) UpdateConfigIfChanged(typ, name, content string) (Cfg, bool, error) {
+	diff, err := c.DiffConfig(typ, name, content)
+	if err != nil {
+		return Cfg{}, false, err
+	}
+	if diff.IsEmpty() {
+		current, err := c.GetConfigs(ConfigsFilter{Type: typ, Name: name, Latest: true})
+		if err != nil {
+			return Cfg{}, false, err
+		}
+		if len(current) > 0 {
+			return current[0], false, nil
+		}
+	}
+
+	cfg, err := c.UpdateConfig(typ, name, content)
+	if err != nil {
+		return Cfg{}, false, err
+	}
+	return cfg, true, nil
+}
@@ -0,0 +1,141 @@
+package gogobosh_test
+
+import (
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+	Describe("Test configs", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"GET", "/configs", configs, ""},
+				{"GET", "/configs/16", config, ""},
+				{"POST", "/configs", config, ""},
+				{"DELETE", "/configs", "", ""},
+				{"DELETE", "/configs/16", "", ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("can get configs with filters", func() {
+			cfgs, err := client.GetConfigs(ConfigsFilter{Type: ConfigTypeRuntime, Name: "dns", Latest: true})
+			Expect(err).Should(BeNil())
+			Expect(cfgs).Should(HaveLen(2))
+			Expect(cfgs[0].ID).Should(Equal("12"))
+			Expect(cfgs[0].CreatedAt).Should(Equal("2022-08-03 22:57:02 UTC"))
+			Expect(cfgs[0].Team).Should(BeEmpty())
+			Expect(cfgs[0].Current).Should(BeTrue())
+			Expect(cfgs[1].Team).Should(Equal("platform"))
+
+			req := receivedRequests["GET /configs"]
+			Expect(req.Query["type"]).Should(Equal([]string{"runtime"}))
+			Expect(req.Query["name"]).Should(Equal([]string{"dns"}))
+			Expect(req.Query["latest"]).Should(Equal([]string{"true"}))
+		})
+
+		It("filters the cloud config by type", func() {
+			_, err := client.GetCloudConfig(false)
+			Expect(err).Should(BeNil())
+			req := receivedRequests["GET /configs"]
+			Expect(req.Query["type"]).Should(Equal([]string{"cloud"}))
+			Expect(req.Query["latest"]).Should(Equal([]string{"false"}))
+		})
+
+		It("can get a config by ID", func() {
+			cfg, err := client.GetConfig("16")
+			Expect(err).Should(BeNil())
+			Expect(cfg.Name).Should(Equal("syslog"))
+			Expect(cfg.Type).Should(Equal("runtime"))
+			Expect(cfg.Content).Should(Equal("releases: []\n"))
+		})
+
+		It("can update a config of any type", func() {
+			cfg, err := client.UpdateConfig(ConfigTypeRuntime, "syslog", "releases: []\n")
+			Expect(err).Should(BeNil())
+			Expect(cfg.ID).Should(Equal("16"))
+			Expect(receivedRequests["POST /configs"].Body).Should(MatchJSON(`{"type": "runtime", "name": "syslog", "content": "releases: []\n"}`))
+		})
+
+		It("can delete configs", func() {
+			Expect(client.DeleteConfig(ConfigTypeRuntime, "syslog")).To(Succeed())
+			req := receivedRequests["DELETE /configs"]
+			Expect(req.Query["type"]).Should(Equal([]string{"runtime"}))
+			Expect(req.Query["name"]).Should(Equal([]string{"syslog"}))
+
+			Expect(client.DeleteConfigByID("16")).To(Succeed())
+		})
+	})
+
+	Describe("Test config diffs", func() {
+		var client *Client
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		newClient := func(diff string) {
+			setupMockRoutes([]MockRoute{
+				{"POST", "/configs/diff", diff, ""},
+				{"GET", "/configs", configs, ""},
+				{"POST", "/configs", config, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		}
+
+		It("can diff a config against the current one", func() {
+			newClient(configDiff)
+			diff, err := client.DiffConfig(ConfigTypeCloud, "default", "azs:\n- name: z1\n- name: z2\n")
+			Expect(err).Should(BeNil())
+			Expect(diff.IsEmpty()).Should(BeFalse())
+			Expect(diff.Lines).Should(HaveLen(4))
+			Expect(diff.Lines[0]).Should(Equal(ConfigDiffLine{Text: "azs:"}))
+			Expect(diff.Lines[2]).Should(Equal(ConfigDiffLine{Text: "- name: z2", State: "added"}))
+			Expect(diff.String()).Should(Equal("  azs:\n  - name: z1\n+ - name: z2\n- - name: z3\n"))
+			Expect(receivedRequests["POST /configs/diff"].Body).Should(MatchJSON(`{"type": "cloud", "name": "default", "content": "azs:\n- name: z1\n- name: z2\n"}`))
+		})
+
+		It("can diff two config versions", func() {
+			newClient(configDiff)
+			_, err := client.DiffConfigByID("12", "16")
+			Expect(err).Should(BeNil())
+			Expect(receivedRequests["POST /configs/diff"].Body).Should(MatchJSON(`{"from": {"id": "12"}, "to": {"id": "16"}}`))
+		})
+
+		It("does not update an unchanged config", func() {
+			newClient(emptyConfigDiff)
+			cfg, updated, err := client.UpdateConfigIfChanged(ConfigTypeCloud, "default", "azs:\n- name: z1\n")
+			Expect(err).Should(BeNil())
+			Expect(updated).Should(BeFalse())
+			Expect(cfg.ID).Should(Equal("12"))
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /configs"))
+		})
+
+		It("updates a changed config", func() {
+			newClient(configDiff)
+			cfg, updated, err := client.UpdateConfigIfChanged(ConfigTypeRuntime, "syslog", "releases: []\n")
+			Expect(err).Should(BeNil())
+			Expect(updated).Should(BeTrue())
+			Expect(cfg.ID).Should(Equal("16"))
+		})
+	})
+})
@@ -0,0 +1,219 @@
+package gogobosh
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CPIConfig is the typed form of a CPI config
+type CPIConfig struct {
+	CPIs []CPI `yaml:"cpis"`
+
+	// Extra keeps the keys that are not modeled, so that a parsed CPI config
+	// can be updated without losing them. The nested types keep theirs the same way.
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// CPI is a cloud provider interface a multi-CPI director deploys to
+type CPI struct {
+	Name         string                 `yaml:"name"`
+	Type         string                 `yaml:"type"`
+	MigratedFrom []CPIMigratedFrom      `yaml:"migrated_from,omitempty"`
+	Properties   map[string]interface{} `yaml:"properties,omitempty"`
+	Extra        map[string]interface{} `yaml:",inline"`
+}
+
+// CPIMigratedFrom names a CPI whose VMs are taken over by another CPI
+type CPIMigratedFrom struct {
+	Name  string                 `yaml:"name"`
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// MissingCPIStemcell is a stemcell a deployment needs that is not uploaded for a CPI
+type MissingCPIStemcell struct {
+	CPI      string
+	Stemcell ManifestStemcell
+}
+
+// ParseCPIConfig parses the YAML content of a CPI config
+func ParseCPIConfig(content string) (CPIConfig, error) {
+	var cc CPIConfig
+	err := yaml.Unmarshal([]byte(content), &cc)
+	if err != nil {
+		return CPIConfig{}, fmt.Errorf("error parsing cpi config: %w", err)
+	}
+	return cc, nil
+}
+
+// Marshal renders the CPI config as YAML
+func (cc CPIConfig) Marshal() (string, error) {
+	b, err := yaml.Marshal(&cc)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling cpi config: %w", err)
+	}
+	return string(b), nil
+}
+
+// Validate checks that every CPI has a unique name and a type, and that
+// migrations are from CPIs that are no longer defined
+func (cc CPIConfig) Validate() error {
+	var errs []error
+	names := map[string]bool{}
+	for _, cpi := range cc.CPIs {
+		if cpi.Name == "" {
+			errs = append(errs, errors.New("cpi without a name"))
+			continue
+		}
+		if names[cpi.Name] {
+			errs = append(errs, fmt.Errorf("duplicate cpi %q", cpi.Name))
+		}
+		names[cpi.Name] = true
+		if cpi.Type == "" {
+			errs = append(errs, fmt.Errorf("cpi %q has no type", cpi.Name))
+		}
+	}
+	for _, cpi := range cc.CPIs {
+		for _, from := range cpi.MigratedFrom {
+			if from.Name == cpi.Name || names[from.Name] {
+				errs = append(errs, fmt.Errorf("cpi %q is migrated from defined cpi %q", cpi.Name, from.Name))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetCPIConfig returns the current default CPI config parsed into a CPIConfig
+func (c *Client) GetCPIConfig() (CPIConfig, error) {
+	cfgs, err := c.GetConfigs(ConfigsFilter{Type: ConfigTypeCPI, Name: "default", Latest: true})
+	if err != nil {
+		return CPIConfig{}, err
+	}
+	if len(cfgs) == 0 {
+		return CPIConfig{}, nil
+	}
+	return ParseCPIConfig(cfgs[0].Content)
+}
+
+// ListCPIs returns the CPIs of the current CPI config
+func (c *Client) ListCPIs() ([]CPI, error) {
+	cc, err := c.GetCPIConfig()
+	if err != nil {
+		return []CPI{}, err
+	}
+	return cc.CPIs, nil
+}
+
+// UpdateCPIConfig validates the CPI config and uploads it as the default CPI
+// config when it differs from the current one. It returns the difference,
+// which is empty when nothing was uploaded.
+func (c *Client) UpdateCPIConfig(cc CPIConfig) (ConfigDiff, error) {
+	err := cc.Validate()
+	if err != nil {
+		return ConfigDiff{}, fmt.Errorf("invalid cpi config: %w", err)
+	}
+	content, err := cc.Marshal()
+	if err != nil {
+		return ConfigDiff{}, err
+	}
+	diff, err := c.DiffConfig(ConfigTypeCPI, "default", content)
+	if err != nil {
+		return ConfigDiff{}, err
+	}
+	if diff.IsEmpty() {
+		return diff, nil
+	}
+	_, err = c.UpdateConfig(ConfigTypeCPI, "default", content)
+	if err != nil {
+		return ConfigDiff{}, err
+	}
+	return diff, nil
+}
+
+// GroupStemcellsByCPI groups stemcells by the CPI they were uploaded to.
+// Stemcells of a single-CPI director are grouped under the empty name.
+func GroupStemcellsByCPI(stemcells []Stemcell) map[string][]Stemcell {
+	groups := map[string][]Stemcell{}
+	for _, stemcell := range stemcells {
+		groups[stemcell.CPI] = append(groups[stemcell.CPI], stemcell)
+	}
+	return groups
+}
+
+// GetStemcellsByCPI returns the uploaded stemcells grouped by CPI
+func (c *Client) GetStemcellsByCPI() (map[string][]Stemcell, error) {
+	stemcells, err := c.GetStemcells()
+	if err != nil {
+		return map[string][]Stemcell{}, err
+	}
+	return GroupStemcellsByCPI(stemcells), nil
+}
+
+// FindMissingCPIStemcells reports, for every CPI, the stemcells of the
+// manifest that have not been uploaded for it. Without CPIs, stemcells are
+// looked up across the whole director.
+func FindMissingCPIStemcells(cpis []CPI, stemcells []Stemcell, manifest DeploymentManifest) []MissingCPIStemcell {
+	groups := GroupStemcellsByCPI(stemcells)
+	if len(cpis) == 0 {
+		groups = map[string][]Stemcell{"": stemcells}
+		cpis = []CPI{{}}
+	}
+
+	var missing []MissingCPIStemcell
+	for _, cpi := range cpis {
+		for _, want := range manifest.Stemcells {
+			if !stemcellsInclude(groups[cpi.Name], want) {
+				missing = append(missing, MissingCPIStemcell{CPI: cpi.Name, Stemcell: want})
+			}
+		}
+	}
+	sort.SliceStable(missing, func(i, j int) bool {
+		return missing[i].CPI < missing[j].CPI
+	})
+	return missing
+}
+
+// stemcellsInclude reports whether one of the stemcells matches the manifest stemcell
+func stemcellsInclude(stemcells []Stemcell, want ManifestStemcell) bool {
+	for _, stemcell := range stemcells {
+		if want.OS != "" && stemcell.OperatingSystem != want.OS {
+			continue
+		}
+		if want.Name != "" && stemcell.Name != want.Name {
+			continue
+		}
+		if stemcellVersionMatches(want.Version, stemcell.Version) {
+			return true
+		}
+	}
+	return false
+}
+
+// stemcellVersionMatches supports the latest and <major>.latest manifest versions
+func stemcellVersionMatches(want, version string) bool {
+	if want == "" || want == "latest" {
+		return true
+	}
+	if strings.HasSuffix(want, ".latest") {
+		major := strings.TrimSuffix(want, "latest")
+		return strings.HasPrefix(version, major)
+	}
+	return want == version
+}
+
+// GetMissingCPIStemcells checks that every CPI of the director has the
+// stemcells the deployment manifest needs
+func (c *Client) GetMissingCPIStemcells(manifest DeploymentManifest) ([]MissingCPIStemcell, error) {
+	cpis, err := c.ListCPIs()
+	if err != nil {
+		return []MissingCPIStemcell{}, err
+	}
+	stemcells, err := c.GetStemcells()
+	if err != nil {
+		return []MissingCPIStemcell{}, err
+	}
+	return FindMissingCPIStemcells(cpis, stemcells, manifest), nil
+}
@@ -0,0 +1,188 @@
+package gogobosh_test
+
+import (
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CPIConfig", func() {
+	Describe("Test typed cpi config", func() {
+		It("can parse a cpi config", func() {
+			cc, err := ParseCPIConfig(typedCPIConfig)
+			Expect(err).Should(BeNil())
+			Expect(cc.CPIs).Should(HaveLen(2))
+			Expect(cc.CPIs[0].Name).Should(Equal("vsphere-dc1"))
+			Expect(cc.CPIs[0].Type).Should(Equal("vsphere"))
+			Expect(cc.CPIs[0].Properties).Should(HaveKeyWithValue("host", "vcenter-1.example.com"))
+			Expect(cc.CPIs[1].MigratedFrom).Should(Equal([]CPIMigratedFrom{{Name: "vsphere-legacy"}}))
+			Expect(cc.Validate()).To(Succeed())
+		})
+
+		It("round trips through YAML", func() {
+			cc, err := ParseCPIConfig(typedCPIConfig)
+			Expect(err).Should(BeNil())
+			content, err := cc.Marshal()
+			Expect(err).Should(BeNil())
+			Expect(content).Should(MatchYAML(typedCPIConfig))
+		})
+
+		It("keeps the keys it does not model", func() {
+			cc, err := ParseCPIConfig(extendedCPIConfig)
+			Expect(err).Should(BeNil())
+			Expect(cc.CPIs[0].Extra).Should(HaveKeyWithValue("exec_path", "/var/vcap/jobs/openstack_cpi/bin/cpi"))
+
+			content, err := cc.Marshal()
+			Expect(err).Should(BeNil())
+			Expect(content).Should(MatchYAML(extendedCPIConfig))
+		})
+
+		It("reports every problem of an invalid cpi config", func() {
+			cc := CPIConfig{CPIs: []CPI{
+				{Name: "aws", Type: "aws"},
+				{Name: "aws"},
+				{Name: "gcp", Type: "google", MigratedFrom: []CPIMigratedFrom{{Name: "aws"}}},
+			}}
+			err := cc.Validate()
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring(`duplicate cpi "aws"`))
+			Expect(err.Error()).Should(ContainSubstring(`cpi "aws" has no type`))
+			Expect(err.Error()).Should(ContainSubstring(`cpi "gcp" is migrated from defined cpi "aws"`))
+		})
+	})
+
+	Describe("Test stemcells by cpi", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"GET", "/configs", cpiConfigs, ""},
+				{"GET", "/stemcells", cpiStemcells, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("can list cpis", func() {
+			cpis, err := client.ListCPIs()
+			Expect(err).Should(BeNil())
+			Expect(receivedRequests["GET /configs"].Query).Should(HaveKeyWithValue("type", []string{"cpi"}))
+			Expect(cpis).Should(HaveLen(2))
+			Expect(cpis[1].Name).Should(Equal("vsphere-dc2"))
+		})
+
+		It("can group stemcells by cpi", func() {
+			groups, err := client.GetStemcellsByCPI()
+			Expect(err).Should(BeNil())
+			Expect(groups).Should(HaveLen(2))
+			Expect(groups["vsphere-dc1"]).Should(HaveLen(2))
+			Expect(groups["vsphere-dc2"]).Should(HaveLen(1))
+			Expect(groups["vsphere-dc2"][0].CID).Should(Equal("sc-4e5f6a7b"))
+		})
+
+		It("reports stemcells missing for a cpi", func() {
+			manifest := DeploymentManifest{Stemcells: []ManifestStemcell{
+				{Alias: "default", OS: "ubuntu-jammy", Version: "1.latest"},
+				{Alias: "windows", OS: "windows2019", Version: "latest"},
+			}}
+			missing, err := client.GetMissingCPIStemcells(manifest)
+			Expect(err).Should(BeNil())
+			Expect(missing).Should(Equal([]MissingCPIStemcell{
+				{CPI: "vsphere-dc2", Stemcell: manifest.Stemcells[1]},
+			}))
+		})
+	})
+
+	Describe("Test missing stemcells", func() {
+		stemcells := []Stemcell{
+			{Name: "bosh-warden-boshlite-ubuntu-jammy-go_agent", OperatingSystem: "ubuntu-jammy", Version: "1.92"},
+		}
+
+		It("looks up stemcells across a single-cpi director", func() {
+			manifest := DeploymentManifest{Stemcells: []ManifestStemcell{
+				{Alias: "default", OS: "ubuntu-jammy", Version: "1.92"},
+				{Alias: "old", OS: "ubuntu-jammy", Version: "1.80"},
+				{Alias: "named", Name: "bosh-warden-boshlite-ubuntu-jammy-go_agent", Version: "latest"},
+			}}
+			missing := FindMissingCPIStemcells(nil, stemcells, manifest)
+			Expect(missing).Should(Equal([]MissingCPIStemcell{
+				{Stemcell: manifest.Stemcells[1]},
+			}))
+		})
+	})
+
+	Describe("Test cpi config update", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"POST", "/configs/diff", configDiff, ""},
+				{"POST", "/configs", config, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("uploads a changed cpi config and returns the difference", func() {
+			cc, err := ParseCPIConfig(typedCPIConfig)
+			Expect(err).Should(BeNil())
+			diff, err := client.UpdateCPIConfig(cc)
+			Expect(err).Should(BeNil())
+			Expect(diff.IsEmpty()).Should(BeFalse())
+			Expect(receivedRequests["POST /configs/diff"].Body).Should(ContainSubstring(`"type":"cpi"`))
+			Expect(receivedRequests["POST /configs"].Body).Should(ContainSubstring(`"name":"default"`))
+		})
+
+		It("refuses to upload an invalid cpi config", func() {
+			_, err := client.UpdateCPIConfig(CPIConfig{CPIs: []CPI{{Name: "aws"}}})
+			Expect(err).Should(HaveOccurred())
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /configs/diff"))
+		})
+	})
+
+	Describe("Test unchanged cpi config", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoute(MockRoute{"POST", "/configs/diff", emptyConfigDiff, ""}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("does not upload an unchanged cpi config", func() {
+			cc, err := ParseCPIConfig(typedCPIConfig)
+			Expect(err).Should(BeNil())
+			diff, err := client.UpdateCPIConfig(cc)
+			Expect(err).Should(BeNil())
+			Expect(diff.IsEmpty()).Should(BeTrue())
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /configs"))
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package gogobosh
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// digest is a single algorithm:value pair of a BOSH multi-digest string
+type digest struct {
+	algorithm string
+	value     string
+}
+
+// digestAlgorithms lists the supported algorithms from strongest to weakest
+var digestAlgorithms = []string{"sha512", "sha256", "sha1"}
+
+// parseDigests parses a BOSH digest string, which is either a bare SHA1 or a
+// semicolon separated list of algorithm:value pairs like "sha256:abc;sha1:def"
+func parseDigests(s string) ([]digest, error) {
+	var digests []digest
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		d := digest{algorithm: "sha1", value: part}
+		if i := strings.Index(part, ":"); i >= 0 {
+			d = digest{algorithm: strings.ToLower(part[:i]), value: part[i+1:]}
+		}
+		h, err := newDigestHash(d.algorithm)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = hex.DecodeString(d.value); err != nil || len(d.value) != h.Size()*2 {
+			return nil, fmt.Errorf("invalid %s digest %q", d.algorithm, d.value)
+		}
+		digests = append(digests, d)
+	}
+	if len(digests) == 0 {
+		return nil, fmt.Errorf("empty digest %q", s)
+	}
+	return digests, nil
+}
+
+// strongestDigest returns the digest using the strongest supported algorithm
+func strongestDigest(digests []digest) digest {
+	for _, algorithm := range digestAlgorithms {
+		for _, d := range digests {
+			if d.algorithm == algorithm {
+				return d
+			}
+		}
+	}
+	return digests[0]
+}
+
+func newDigestHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case "sha1":
+		return sha1.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	}
+	return nil, fmt.Errorf("unsupported digest algorithm %q", algorithm)
+}
+
+// verify checks the hash sum against the expected digest value
+func (d digest) verify(h hash.Hash) error {
+	actual := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(actual, d.value) {
+		return fmt.Errorf("expected %s digest %s but got %s", d.algorithm, d.value, actual)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package gogobosh
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// AttachDiskOptions configures how a disk is attached to an instance
+type AttachDiskOptions struct {
+	// DiskProperties are the properties recorded for the attached disk, either
+	// "copy" to copy them from the disk the instance had or the name of a disk
+	// type from the cloud config. Defaults to "copy".
+	DiskProperties string
+}
+
+// GetOrphanedDisks returns the persistent disks orphaned by the given BOSH
+func (c *Client) GetOrphanedDisks() ([]OrphanedDisk, error) {
+	r := c.NewRequest("GET", "/disks?orphaned=true")
+	var disks []OrphanedDisk
+	err := c.DoRequestAndUnmarshal(r, &disks)
+	if err != nil {
+		return []OrphanedDisk{}, fmt.Errorf("error requesting orphaned disks: %w", err)
+	}
+	return disks, nil
+}
+
+// OrphanDisk detaches the persistent disk from its instance and keeps it as
+// an orphaned disk, so that it can be attached again or deleted later
+func (c *Client) OrphanDisk(cid string) (Task, error) {
+	r := c.NewRequest("DELETE", "/disks/"+cid+"?orphan=true")
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error orphaning disk %s: %w", cid, err)
+	}
+	return task, nil
+}
+
+// DeleteOrphanedDisk permanently deletes the orphaned disk from the IaaS
+func (c *Client) DeleteOrphanedDisk(cid string) (Task, error) {
+	r := c.NewRequest("DELETE", "/disks/"+cid)
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error deleting orphaned disk %s: %w", cid, err)
+	}
+	return task, nil
+}
+
+// AttachDisk attaches the disk to the instance as its persistent disk. The
+// instance must be stopped with StopHard first, and any disk it had is orphaned.
+func (c *Client) AttachDisk(deployment, instanceGroup, instanceID, diskCID string) (Task, error) {
+	return c.AttachDiskWithOptions(deployment, instanceGroup, instanceID, diskCID, AttachDiskOptions{})
+}
+
+// AttachDiskWithOptions attaches the disk to the instance like AttachDisk
+func (c *Client) AttachDiskWithOptions(deployment, instanceGroup, instanceID, diskCID string, opts AttachDiskOptions) (Task, error) {
+	diskProperties := opts.DiskProperties
+	if diskProperties == "" {
+		diskProperties = "copy"
+	}
+	query := url.Values{}
+	query.Set("deployment", deployment)
+	query.Set("job", instanceGroup)
+	query.Set("instance_id", instanceID)
+	query.Set("disk_properties", diskProperties)
+
+	r := c.NewRequest("PUT", "/disks/"+diskCID+"/attachments?"+query.Encode())
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error attaching disk %s to %s/%s: %w", diskCID, instanceGroup, instanceID, err)
+	}
+	return task, nil
+}
+
+// GetInstanceDisks returns the persistent disks of every instance of the
+// deployment, including instances that currently have no VM. The director
+// does not report the size and cloud properties of attached disks, they are
+// resolved from the deployment manifest and the default cloud config.
+func (c *Client) GetInstanceDisks(deployment string) ([]InstanceDisks, error) {
+	var disks []InstanceDisks
+	err := c.streamFullDeploymentRows(deployment, "instances", DefaultVMsOptions(), func(vm VM) error {
+		disks = append(disks, InstanceDisks{
+			InstanceGroup: vm.JobName,
+			ID:            vm.ID,
+			AZ:            vm.AZ,
+			DiskCIDs:      vm.DiskCIDs,
+		})
+		return nil
+	})
+	if err != nil {
+		return []InstanceDisks{}, err
+	}
+
+	m, err := c.GetDeployment(deployment)
+	if err != nil {
+		return []InstanceDisks{}, err
+	}
+	manifest, err := m.Parse()
+	if err != nil {
+		return []InstanceDisks{}, err
+	}
+	cc, err := c.GetTypedCloudConfig()
+	if err != nil {
+		return []InstanceDisks{}, err
+	}
+	for i := range disks {
+		for _, ig := range manifest.InstanceGroups {
+			if ig.Name == disks[i].InstanceGroup {
+				disks[i].DiskType, disks[i].Size, disks[i].CloudProperties = ig.persistentDisk(cc)
+			}
+		}
+	}
+	return disks, nil
+}
@@ -0,0 +1,106 @@
+package gogobosh_test
+
+import (
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Disk", func() {
+	Describe("Test disks", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"GET", "/disks", orphanedDisks, ""},
+				{"DELETE", "/disks/disk-3f2a9c1e-5b7d-4e8f-a0b1-c2d3e4f5a6b7", "", "/tasks/9"},
+				{"PUT", "/disks/disk-3f2a9c1e-5b7d-4e8f-a0b1-c2d3e4f5a6b7/attachments", "", "/tasks/9"},
+				{"GET", "/tasks/9", diskTask, ""},
+				{"GET", "/deployments/cf-warden/instances", "", "/tasks/2"},
+				{"GET", "/tasks/2", task, ""},
+				{"GET", "/tasks/2/output", instances, ""},
+				{"GET", "/deployments/cf-warden", diskManifest, ""},
+				{"GET", "/configs", diskCloudConfigs, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("can get orphaned disks", func() {
+			disks, err := client.GetOrphanedDisks()
+			Expect(err).Should(BeNil())
+			Expect(disks).Should(HaveLen(1))
+			Expect(disks[0].DiskCID).Should(Equal("disk-3f2a9c1e-5b7d-4e8f-a0b1-c2d3e4f5a6b7"))
+			Expect(disks[0].Size).Should(Equal(10240))
+			Expect(disks[0].AZ).Should(Equal("z1"))
+			Expect(disks[0].Deployment).Should(Equal("cf-warden"))
+			Expect(disks[0].Instance).Should(Equal("database/2b8f6c1d-9e3a-4f5b-8c7d-6e5f4a3b2c1d"))
+			Expect(disks[0].CloudProperties).Should(HaveKeyWithValue("type", "gp2"))
+			Expect(disks[0].OrphanedAt).Should(Equal("2022-08-03 22:57:02 UTC"))
+			Expect(receivedRequests["GET /disks"].Query["orphaned"]).Should(Equal([]string{"true"}))
+		})
+
+		It("can orphan a disk", func() {
+			task, err := client.OrphanDisk("disk-3f2a9c1e-5b7d-4e8f-a0b1-c2d3e4f5a6b7")
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(9))
+			Expect(receivedRequests["DELETE /disks/disk-3f2a9c1e-5b7d-4e8f-a0b1-c2d3e4f5a6b7"].Query).Should(Equal(map[string][]string{
+				"orphan": {"true"},
+			}))
+		})
+
+		It("can delete an orphaned disk", func() {
+			task, err := client.DeleteOrphanedDisk("disk-3f2a9c1e-5b7d-4e8f-a0b1-c2d3e4f5a6b7")
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(9))
+			Expect(receivedRequests["DELETE /disks/disk-3f2a9c1e-5b7d-4e8f-a0b1-c2d3e4f5a6b7"].Query).Should(BeEmpty())
+		})
+
+		It("can attach a disk to an instance", func() {
+			task, err := client.AttachDisk("cf-warden", "database", "2b8f6c1d-9e3a-4f5b-8c7d-6e5f4a3b2c1d", "disk-3f2a9c1e-5b7d-4e8f-a0b1-c2d3e4f5a6b7")
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(9))
+
+			req := receivedRequests["PUT /disks/disk-3f2a9c1e-5b7d-4e8f-a0b1-c2d3e4f5a6b7/attachments"]
+			Expect(req.Query["deployment"]).Should(Equal([]string{"cf-warden"}))
+			Expect(req.Query["job"]).Should(Equal([]string{"database"}))
+			Expect(req.Query["instance_id"]).Should(Equal([]string{"2b8f6c1d-9e3a-4f5b-8c7d-6e5f4a3b2c1d"}))
+			Expect(req.Query["disk_properties"]).Should(Equal([]string{"copy"}))
+		})
+
+		It("can attach a disk with the properties of a disk type", func() {
+			_, err := client.AttachDiskWithOptions("cf-warden", "database", "2b8f6c1d-9e3a-4f5b-8c7d-6e5f4a3b2c1d", "disk-3f2a9c1e-5b7d-4e8f-a0b1-c2d3e4f5a6b7", AttachDiskOptions{
+				DiskProperties: "large",
+			})
+			Expect(err).Should(BeNil())
+
+			req := receivedRequests["PUT /disks/disk-3f2a9c1e-5b7d-4e8f-a0b1-c2d3e4f5a6b7/attachments"]
+			Expect(req.Query["disk_properties"]).Should(Equal([]string{"large"}))
+		})
+
+		It("can list the disks of every instance", func() {
+			disks, err := client.GetInstanceDisks("cf-warden")
+			Expect(err).Should(BeNil())
+			Expect(disks).Should(Equal([]InstanceDisks{
+				{
+					InstanceGroup: "database", ID: "2b8f6c1d-9e3a-4f5b-8c7d-6e5f4a3b2c1d", AZ: "z1", DiskCIDs: []string{"disk-8a7b6c5d"},
+					DiskType: "large", Size: 10240, CloudProperties: map[string]interface{}{"type": "gp2"},
+				},
+				{
+					InstanceGroup: "database", ID: "7c6d5e4f-3a2b-4c1d-9e8f-0a1b2c3d4e5f", AZ: "z2", DiskCIDs: []string{"disk-1a2b3c4d"},
+					DiskType: "large", Size: 10240, CloudProperties: map[string]interface{}{"type": "gp2"},
+				},
+			}))
+			Expect(receivedRequests["GET /configs"].Query).Should(HaveKeyWithValue("type", []string{"cloud"}))
+		})
+	})
+})
@@ -0,0 +1,93 @@
+package gogobosh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrandOptions configures how an errand is run
+type ErrandOptions struct {
+	// KeepAlive keeps the errand VMs around after the errand finishes
+	KeepAlive bool
+	// WhenChanged only runs the errand if its configuration changed since the last successful run
+	WhenChanged bool
+	// Instances restricts the errand to the given instance groups or instances
+	Instances []InstanceSlug
+	// TaskWaitOptions bounds the wait for the errand task, which defaults to 30 minutes
+	TaskWaitOptions
+}
+
+// GetErrands returns the errands available in the specified deployment
+func (c *Client) GetErrands(deployment string) ([]Errand, error) {
+	r := c.NewRequest("GET", "/deployments/"+deployment+"/errands")
+	var errands []Errand
+	err := c.DoRequestAndUnmarshal(r, &errands)
+	if err != nil {
+		return []Errand{}, fmt.Errorf("error requesting deployment %s errands: %w", deployment, err)
+	}
+	return errands, nil
+}
+
+// RunErrand runs the errand in the specified deployment, waits for it to
+// finish and returns the result from every instance it ran on
+func (c *Client) RunErrand(deployment, errand string, opts ErrandOptions) ([]ErrandResult, error) {
+	r := c.NewRequest("POST", "/deployments/"+deployment+"/errands/"+errand+"/runs")
+	in := struct {
+		KeepAlive   bool           `json:"keep_alive"`
+		WhenChanged bool           `json:"when_changed"`
+		Instances   []InstanceSlug `json:"instances"`
+	}{
+		KeepAlive:   opts.KeepAlive,
+		WhenChanged: opts.WhenChanged,
+		Instances:   opts.Instances,
+	}
+	if in.Instances == nil {
+		in.Instances = []InstanceSlug{}
+	}
+
+	b, err := json.Marshal(&in)
+	if err != nil {
+		return []ErrandResult{}, fmt.Errorf("error marshalling run errand request: %w", err)
+	}
+	r.body = bytes.NewBuffer(b)
+	r.header["Content-Type"] = "application/json"
+
+	var task Task
+	err = c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return []ErrandResult{}, fmt.Errorf("error running errand %s in deployment %s: %w", errand, deployment, err)
+	}
+
+	task, err = c.WaitUntilDoneWithInterval(task, opts.timeout(time.Minute*30), opts.pollInterval())
+	if err != nil {
+		return []ErrandResult{}, fmt.Errorf("error waiting for errand %s task to complete: %w", errand, err)
+	}
+
+	return c.GetErrandResults(task.ID)
+}
+
+// GetErrandResults parses the errand results from the result of a completed run errand task
+func (c *Client) GetErrandResults(taskID int) ([]ErrandResult, error) {
+	output, err := c.getTaskOutputReader(taskID, "result")
+	if err != nil {
+		return []ErrandResult{}, fmt.Errorf("error getting errand task %d result: %w", taskID, err)
+	}
+	defer func() { _ = output.Close() }()
+
+	var results []ErrandResult
+	dec := json.NewDecoder(output)
+	for {
+		var result ErrandResult
+		err = dec.Decode(&result)
+		if err == io.EOF {
+			return results, nil
+		}
+		if err != nil {
+			return []ErrandResult{}, fmt.Errorf("error unmarshalling errand task %d result: %w", taskID, err)
+		}
+		results = append(results, result)
+	}
+}
@@ -0,0 +1,108 @@
+package gogobosh_test
+
+import (
+	"time"
+
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Errand", func() {
+	Describe("Test errands", func() {
+		var client *Client
+
+		Describe("Test get errands", func() {
+			BeforeEach(func() {
+				setupMockRoute(MockRoute{"GET", "/deployments/cf-warden/errands", errands, ""}, "basic")
+				config := &Config{
+					BOSHAddress: server.URL,
+					Username:    "admin",
+					Password:    "admin",
+				}
+
+				client, _ = NewClient(config)
+			})
+
+			AfterEach(func() {
+				teardown()
+			})
+
+			It("can get errands", func() {
+				errands, err := client.GetErrands("cf-warden")
+				Expect(err).Should(BeNil())
+				Expect(errands).Should(HaveLen(2))
+				Expect(errands[0].Name).Should(Equal("smoke_tests"))
+				Expect(errands[1].Name).Should(Equal("acceptance_tests"))
+			})
+		})
+
+		Describe("Test run errand", func() {
+			BeforeEach(func() {
+				setupMockRoutes([]MockRoute{
+					{"POST", "/deployments/cf-warden/errands/smoke_tests/runs", "", "/tasks/5"},
+					{"GET", "/tasks/5", errandTask, ""},
+					{"GET", "/tasks/5/output", errandResults, ""},
+				}, "basic")
+				config := &Config{
+					BOSHAddress: server.URL,
+					Username:    "admin",
+					Password:    "admin",
+				}
+
+				client, _ = NewClient(config)
+			})
+
+			AfterEach(func() {
+				teardown()
+			})
+
+			It("can run an errand and parse its results", func() {
+				results, err := client.RunErrand("cf-warden", "smoke_tests", ErrandOptions{
+					KeepAlive:       true,
+					Instances:       []InstanceSlug{{Group: "smoke_tests"}},
+					TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10},
+				})
+				Expect(err).Should(BeNil())
+				Expect(results).Should(HaveLen(2))
+				Expect(results[0].Instance.Group).Should(Equal("smoke_tests"))
+				Expect(results[0].Instance.ID).Should(Equal("1b5a4e6c-2f5d-4d3e-9b8a-7c6d5e4f3a2b"))
+				Expect(results[0].ErrandName).Should(Equal("smoke_tests"))
+				Expect(results[0].ExitCode).Should(Equal(0))
+				Expect(results[0].Stdout).Should(Equal("all tests passed\n"))
+				Expect(results[0].Logs.BlobstoreID).Should(Equal("6b1ec4ab-a7d8-4d0b-8d76-b9e5d5d4b8d1"))
+				Expect(results[1].ExitCode).Should(Equal(1))
+				Expect(results[1].Stderr).Should(Equal("1 test failed\n"))
+				Expect(receivedRequests["POST /deployments/cf-warden/errands/smoke_tests/runs"].Body).Should(MatchJSON(`{
+					"keep_alive": true,
+					"when_changed": false,
+					"instances": [{"group": "smoke_tests"}]
+				}`))
+			})
+
+			It("sends the errand options", func() {
+				_, err := client.RunErrand("cf-warden", "smoke_tests", ErrandOptions{
+					WhenChanged:     true,
+					Instances:       []InstanceSlug{{Group: "smoke_tests", ID: "1b5a4e6c-2f5d-4d3e-9b8a-7c6d5e4f3a2b"}},
+					TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10},
+				})
+				Expect(err).Should(BeNil())
+				Expect(receivedRequests["POST /deployments/cf-warden/errands/smoke_tests/runs"].Body).Should(MatchJSON(`{
+					"keep_alive": false,
+					"when_changed": true,
+					"instances": [{"group": "smoke_tests", "id": "1b5a4e6c-2f5d-4d3e-9b8a-7c6d5e4f3a2b"}]
+				}`))
+			})
+
+			It("runs the errand on every instance by default", func() {
+				_, err := client.RunErrand("cf-warden", "smoke_tests", ErrandOptions{TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10}})
+				Expect(err).Should(BeNil())
+				Expect(receivedRequests["POST /deployments/cf-warden/errands/smoke_tests/runs"].Body).Should(MatchJSON(`{
+					"keep_alive": false,
+					"when_changed": false,
+					"instances": []
+				}`))
+			})
+		})
+	})
+})
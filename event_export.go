@@ -0,0 +1,353 @@
+package gogobosh
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventExporter writes director events to an audit sink
+type EventExporter interface {
+	Export(event Event) error
+}
+
+// ExportEvents exports every event received on the channel, such as the
+// Events channel of an EventWatcher, until it is closed
+func ExportEvents(exporter EventExporter, events <-chan Event) error {
+	for event := range events {
+		err := exporter.Export(event)
+		if err != nil {
+			return fmt.Errorf("error exporting event %s: %w", event.ID, err)
+		}
+	}
+	return nil
+}
+
+// eventRecord is the JSON form of an exported event
+type eventRecord struct {
+	ID         string                 `json:"id"`
+	ParentID   string                 `json:"parent_id,omitempty"`
+	Time       string                 `json:"time"`
+	User       string                 `json:"user"`
+	Action     string                 `json:"action"`
+	ObjectType string                 `json:"object_type"`
+	ObjectName string                 `json:"object_name,omitempty"`
+	Task       string                 `json:"task,omitempty"`
+	Deployment string                 `json:"deployment,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	Context    map[string]interface{} `json:"context,omitempty"`
+}
+
+func eventTime(event Event) time.Time {
+	return time.Unix(int64(event.Timestamp), 0).UTC()
+}
+
+// eventSummary describes the event in a short sentence
+func eventSummary(event Event) string {
+	parts := []string{event.User, event.Action, event.ObjectType}
+	if event.ObjectName != "" {
+		parts = append(parts, event.ObjectName)
+	}
+	summary := strings.Join(parts, " ")
+	if event.Error != "" {
+		summary += " failed: " + event.Error
+	}
+	return summary
+}
+
+// JSONLinesExporter writes events as one JSON object per line
+type JSONLinesExporter struct {
+	w    io.Writer
+	lock sync.Mutex
+}
+
+// NewJSONLinesExporter returns an exporter writing JSON Lines to w
+func NewJSONLinesExporter(w io.Writer) *JSONLinesExporter {
+	return &JSONLinesExporter{w: w}
+}
+
+// Export writes the event as a JSON line
+func (e *JSONLinesExporter) Export(event Event) error {
+	b, err := json.Marshal(eventRecord{
+		ID:         event.ID,
+		ParentID:   event.ParentID,
+		Time:       eventTime(event).Format(time.RFC3339),
+		User:       event.User,
+		Action:     event.Action,
+		ObjectType: event.ObjectType,
+		ObjectName: event.ObjectName,
+		Task:       event.Task,
+		Deployment: event.Deployment,
+		Error:      event.Error,
+		Context:    event.Context,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshalling event: %w", err)
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	_, err = e.w.Write(append(b, '\n'))
+	return err
+}
+
+// SyslogOptions configure a SyslogExporter
+type SyslogOptions struct {
+	// Hostname sent with every message, defaults to the local hostname
+	Hostname string
+	// AppName sent with every message, defaults to bosh-director
+	AppName string
+	// Facility of the messages from 0 to 23, defaults to 13 (log audit) when nil
+	Facility *int
+	// SDID is the structured data ID events are sent under, defaults to bosh@32473
+	SDID string
+}
+
+func (o SyslogOptions) hostname() string {
+	if o.Hostname != "" {
+		return o.Hostname
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return hostname
+}
+
+func (o SyslogOptions) appName() string {
+	if o.AppName == "" {
+		return "bosh-director"
+	}
+	return o.AppName
+}
+
+func (o SyslogOptions) facility() int {
+	if o.Facility == nil {
+		return 13
+	}
+	return *o.Facility
+}
+
+func (o SyslogOptions) sdID() string {
+	if o.SDID == "" {
+		return "bosh@32473"
+	}
+	return o.SDID
+}
+
+// SyslogExporter sends events as RFC 5424 syslog messages. Over UDP every
+// message is a datagram, over TCP messages are framed with octet counting
+// as described in RFC 6587.
+type SyslogExporter struct {
+	conn         net.Conn
+	octetCounted bool
+	hostname     string
+	opts         SyslogOptions
+	lock         sync.Mutex
+}
+
+// DialSyslogExporter connects to the syslog server at addr over the network,
+// "udp" or "tcp"
+func DialSyslogExporter(network, addr string, opts SyslogOptions) (*SyslogExporter, error) {
+	if network != "udp" && network != "tcp" {
+		return nil, fmt.Errorf("unsupported syslog network %s", network)
+	}
+	if facility := opts.facility(); facility < 0 || facility > 23 {
+		return nil, fmt.Errorf("invalid syslog facility %d", facility)
+	}
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to syslog server %s: %w", addr, err)
+	}
+	return &SyslogExporter{
+		conn:         conn,
+		octetCounted: network == "tcp",
+		hostname:     opts.hostname(),
+		opts:         opts,
+	}, nil
+}
+
+// Export sends the event as a syslog message
+func (e *SyslogExporter) Export(event Event) error {
+	msg, err := e.format(event)
+	if err != nil {
+		return err
+	}
+	if e.octetCounted {
+		msg = strconv.Itoa(len(msg)) + " " + msg
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	_, err = io.WriteString(e.conn, msg)
+	if err != nil {
+		return fmt.Errorf("error sending syslog message: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection to the syslog server
+func (e *SyslogExporter) Close() error {
+	return e.conn.Close()
+}
+
+func (e *SyslogExporter) format(event Event) (string, error) {
+	severity := 6
+	if event.Error != "" {
+		severity = 3
+	}
+
+	params := [][2]string{
+		{"id", event.ID},
+		{"user", event.User},
+		{"action", event.Action},
+		{"object_type", event.ObjectType},
+		{"object_name", event.ObjectName},
+		{"deployment", event.Deployment},
+		{"task", event.Task},
+		{"error", event.Error},
+	}
+	if len(event.Context) > 0 {
+		b, err := json.Marshal(event.Context)
+		if err != nil {
+			return "", fmt.Errorf("error marshalling event context: %w", err)
+		}
+		params = append(params, [2]string{"context", string(b)})
+	}
+	sd := "[" + e.opts.sdID()
+	for _, param := range params {
+		if param[1] != "" {
+			sd += " " + param[0] + `="` + syslogParamEscaper.Replace(param[1]) + `"`
+		}
+	}
+	sd += "]"
+
+	return fmt.Sprintf("<%d>1 %s %s %s - %s %s %s",
+		e.opts.facility()*8+severity,
+		eventTime(event).Format(time.RFC3339),
+		syslogHeaderField(e.hostname, 255),
+		syslogHeaderField(e.opts.appName(), 48),
+		syslogHeaderField(event.Action, 32),
+		sd,
+		eventSummary(event),
+	), nil
+}
+
+var syslogParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// syslogHeaderField restricts a header field to printable ASCII without
+// spaces and the maximum length, using the nil value when empty
+func syslogHeaderField(s string, max int) string {
+	field := strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(field) > max {
+		field = field[:max]
+	}
+	if field == "" {
+		return "-"
+	}
+	return field
+}
+
+// CEFOptions configure a CEFExporter
+type CEFOptions struct {
+	// DeviceVendor defaults to Cloud Foundry
+	DeviceVendor string
+	// DeviceProduct defaults to BOSH Director
+	DeviceProduct string
+	// DeviceVersion is the director version, such as Info.Version
+	DeviceVersion string
+}
+
+// CEFExporter writes events as ArcSight Common Event Format lines
+type CEFExporter struct {
+	w    io.Writer
+	opts CEFOptions
+	lock sync.Mutex
+}
+
+// NewCEFExporter returns an exporter writing CEF lines to w
+func NewCEFExporter(w io.Writer, opts CEFOptions) *CEFExporter {
+	if opts.DeviceVendor == "" {
+		opts.DeviceVendor = "Cloud Foundry"
+	}
+	if opts.DeviceProduct == "" {
+		opts.DeviceProduct = "BOSH Director"
+	}
+	return &CEFExporter{w: w, opts: opts}
+}
+
+// Export writes the event as a CEF line
+func (e *CEFExporter) Export(event Event) error {
+	line, err := e.format(event)
+	if err != nil {
+		return err
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	_, err = io.WriteString(e.w, line+"\n")
+	return err
+}
+
+func (e *CEFExporter) format(event Event) (string, error) {
+	severity, outcome := "3", "success"
+	if event.Error != "" {
+		severity, outcome = "7", "failure"
+	}
+
+	extensions := [][2]string{
+		{"rt", strconv.FormatInt(eventTime(event).UnixMilli(), 10)},
+		{"externalId", event.ID},
+		{"suser", event.User},
+		{"act", event.Action},
+		{"outcome", outcome},
+		{"reason", event.Error},
+		{"cs1Label", "deployment"},
+		{"cs1", event.Deployment},
+		{"cs2Label", "objectType"},
+		{"cs2", event.ObjectType},
+		{"cs3Label", "objectName"},
+		{"cs3", event.ObjectName},
+		{"cs4Label", "task"},
+		{"cs4", event.Task},
+	}
+	if len(event.Context) > 0 {
+		b, err := json.Marshal(event.Context)
+		if err != nil {
+			return "", fmt.Errorf("error marshalling event context: %w", err)
+		}
+		extensions = append(extensions, [2]string{"cs5Label", "context"}, [2]string{"cs5", string(b)})
+	}
+	var ext []string
+	for _, extension := range extensions {
+		if extension[1] != "" {
+			ext = append(ext, extension[0]+"="+cefExtensionEscaper.Replace(extension[1]))
+		}
+	}
+
+	header := []string{
+		"CEF:0",
+		cefHeaderEscaper.Replace(e.opts.DeviceVendor),
+		cefHeaderEscaper.Replace(e.opts.DeviceProduct),
+		cefHeaderEscaper.Replace(e.opts.DeviceVersion),
+		cefHeaderEscaper.Replace(event.ObjectType + ":" + event.Action),
+		cefHeaderEscaper.Replace(eventSummary(event)),
+		severity,
+	}
+	return strings.Join(header, "|") + "|" + strings.Join(ext, " "), nil
+}
+
+var cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+
+var cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
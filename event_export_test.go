@@ -0,0 +1,186 @@
+package gogobosh_test
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventExport", func() {
+	deployEvent := Event{
+		ID:         "1042",
+		ParentID:   "1041",
+		Timestamp:  1659657425,
+		User:       "admin",
+		Action:     "update",
+		ObjectType: "deployment",
+		ObjectName: "cf",
+		Task:       "312",
+		Deployment: "cf",
+		Context:    map[string]interface{}{"new_name": "cf"},
+	}
+	sshEvent := Event{
+		ID:         "1043",
+		Timestamp:  1659657500,
+		User:       "ops|team=a",
+		Action:     "setup ssh",
+		ObjectType: "instance",
+		ObjectName: "router/4a9278c8",
+		Deployment: "cf",
+		Error:      `host "key" rejected`,
+	}
+
+	Describe("Test JSON Lines export", func() {
+		It("writes one JSON object per event", func() {
+			var out bytes.Buffer
+			exporter := NewJSONLinesExporter(&out)
+			Expect(exporter.Export(deployEvent)).To(Succeed())
+			Expect(exporter.Export(sshEvent)).To(Succeed())
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			Expect(lines).Should(HaveLen(2))
+			Expect(lines[0]).Should(MatchJSON(`{
+				"id": "1042",
+				"parent_id": "1041",
+				"time": "2022-08-04T23:57:05Z",
+				"user": "admin",
+				"action": "update",
+				"object_type": "deployment",
+				"object_name": "cf",
+				"task": "312",
+				"deployment": "cf",
+				"context": {"new_name": "cf"}
+			}`))
+			Expect(lines[1]).Should(ContainSubstring(`"error":"host \"key\" rejected"`))
+		})
+
+		It("exports the events of a channel", func() {
+			var out bytes.Buffer
+			events := make(chan Event, 2)
+			events <- deployEvent
+			events <- sshEvent
+			close(events)
+			Expect(ExportEvents(NewJSONLinesExporter(&out), events)).To(Succeed())
+			Expect(strings.Count(out.String(), "\n")).Should(Equal(2))
+		})
+	})
+
+	Describe("Test CEF export", func() {
+		It("writes events as CEF lines", func() {
+			var out bytes.Buffer
+			exporter := NewCEFExporter(&out, CEFOptions{DeviceVersion: "280.0.14"})
+			Expect(exporter.Export(deployEvent)).To(Succeed())
+			Expect(exporter.Export(sshEvent)).To(Succeed())
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			Expect(lines).Should(HaveLen(2))
+			Expect(lines[0]).Should(Equal(`CEF:0|Cloud Foundry|BOSH Director|280.0.14|deployment:update|admin update deployment cf|3|` +
+				`rt=1659657425000 externalId=1042 suser=admin act=update outcome=success cs1Label=deployment cs1=cf ` +
+				`cs2Label=objectType cs2=deployment cs3Label=objectName cs3=cf cs4Label=task cs4=312 ` +
+				`cs5Label=context cs5={"new_name":"cf"}`))
+			Expect(lines[1]).Should(HavePrefix(`CEF:0|Cloud Foundry|BOSH Director|280.0.14|instance:setup ssh|ops\|team=a setup ssh instance router/4a9278c8 failed: host "key" rejected|7|`))
+			Expect(lines[1]).Should(ContainSubstring(`suser=ops|team\=a act=setup ssh outcome=failure reason=host "key" rejected`))
+		})
+	})
+
+	Describe("Test syslog export", func() {
+		It("sends RFC 5424 messages over UDP", func() {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			Expect(err).Should(BeNil())
+			defer func() { _ = conn.Close() }()
+
+			exporter, err := DialSyslogExporter("udp", conn.LocalAddr().String(), SyslogOptions{Hostname: "director"})
+			Expect(err).Should(BeNil())
+			defer func() { _ = exporter.Close() }()
+			Expect(exporter.Export(deployEvent)).To(Succeed())
+			Expect(exporter.Export(sshEvent)).To(Succeed())
+
+			buf := make([]byte, 4096)
+			n, _, err := conn.ReadFrom(buf)
+			Expect(err).Should(BeNil())
+			Expect(string(buf[:n])).Should(Equal(`<110>1 2022-08-04T23:57:05Z director bosh-director - update ` +
+				`[bosh@32473 id="1042" user="admin" action="update" object_type="deployment" object_name="cf" deployment="cf" task="312" context="{\"new_name\":\"cf\"}"] ` +
+				`admin update deployment cf`))
+
+			n, _, err = conn.ReadFrom(buf)
+			Expect(err).Should(BeNil())
+			Expect(string(buf[:n])).Should(HavePrefix(`<107>1 2022-08-04T23:58:20Z director bosh-director - setup_ssh [bosh@32473 id="1043"`))
+			Expect(string(buf[:n])).Should(ContainSubstring(`error="host \"key\" rejected"`))
+		})
+
+		It("sends octet counted messages over TCP", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).Should(BeNil())
+			defer func() { _ = listener.Close() }()
+
+			received := make(chan []string, 1)
+			go func() {
+				defer GinkgoRecover()
+				conn, err := listener.Accept()
+				Expect(err).Should(BeNil())
+				defer func() { _ = conn.Close() }()
+				r := bufio.NewReader(conn)
+				var messages []string
+				for i := 0; i < 2; i++ {
+					length, err := r.ReadString(' ')
+					Expect(err).Should(BeNil())
+					n, err := strconv.Atoi(strings.TrimSpace(length))
+					Expect(err).Should(BeNil())
+					msg := make([]byte, n)
+					_, err = io.ReadFull(r, msg)
+					Expect(err).Should(BeNil())
+					messages = append(messages, string(msg))
+				}
+				received <- messages
+			}()
+
+			facility := 10
+			exporter, err := DialSyslogExporter("tcp", listener.Addr().String(), SyslogOptions{Hostname: "director", AppName: "audit", Facility: &facility})
+			Expect(err).Should(BeNil())
+			defer func() { _ = exporter.Close() }()
+			Expect(exporter.Export(deployEvent)).To(Succeed())
+			Expect(exporter.Export(sshEvent)).To(Succeed())
+
+			var messages []string
+			Eventually(received).Should(Receive(&messages))
+			Expect(messages[0]).Should(HavePrefix(`<86>1 2022-08-04T23:57:05Z director audit - update [bosh@32473 id="1042"`))
+			Expect(messages[0]).Should(HaveSuffix(`admin update deployment cf`))
+			Expect(messages[1]).Should(HavePrefix(`<83>1 2022-08-04T23:58:20Z director audit - setup_ssh`))
+		})
+
+		It("can send messages with the kernel facility", func() {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			Expect(err).Should(BeNil())
+			defer func() { _ = conn.Close() }()
+
+			kern := 0
+			exporter, err := DialSyslogExporter("udp", conn.LocalAddr().String(), SyslogOptions{Hostname: "director", Facility: &kern})
+			Expect(err).Should(BeNil())
+			defer func() { _ = exporter.Close() }()
+			Expect(exporter.Export(deployEvent)).To(Succeed())
+
+			buf := make([]byte, 4096)
+			n, _, err := conn.ReadFrom(buf)
+			Expect(err).Should(BeNil())
+			Expect(string(buf[:n])).Should(HavePrefix(`<6>1 2022-08-04T23:57:05Z director bosh-director - update`))
+		})
+
+		It("refuses invalid facilities", func() {
+			facility := 24
+			_, err := DialSyslogExporter("udp", "127.0.0.1:514", SyslogOptions{Facility: &facility})
+			Expect(err).Should(MatchError("invalid syslog facility 24"))
+		})
+
+		It("refuses unsupported networks", func() {
+			_, err := DialSyslogExporter("unix", "/dev/log", SyslogOptions{})
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,198 @@
+package gogobosh
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// EventWatcherOptions configure an EventWatcher
+type EventWatcherOptions struct {
+	// Filter restricts the watched events. BeforeID, Before and Limit are ignored.
+	Filter EventsFilter
+	// PollInterval is the time between polls of the director, also used to
+	// retry after errors. Defaults to 10 seconds.
+	PollInterval time.Duration
+	// CheckpointFile, if set, stores the ID of the last delivered event so a
+	// restarted watcher resumes where it stopped
+	CheckpointFile string
+}
+
+func (o EventWatcherOptions) pollInterval() time.Duration {
+	if o.PollInterval <= 0 {
+		return 10 * time.Second
+	}
+	return o.PollInterval
+}
+
+// EventWatcher polls the director for new events and delivers them in
+// ascending ID order on the Events channel. Errors talking to the director
+// are sent on the Errors channel, when it is being read, and retried.
+type EventWatcher struct {
+	Events <-chan Event
+	Errors <-chan error
+
+	client *Client
+	opts   EventWatcherOptions
+	events chan Event
+	errors chan error
+	lastID int
+}
+
+// NewEventWatcher returns a watcher for new director events. It resumes from
+// the checkpoint file when there is one, and otherwise starts after the
+// newest event at the time Run is called.
+func (c *Client) NewEventWatcher(opts EventWatcherOptions) (*EventWatcher, error) {
+	w := &EventWatcher{
+		client: c,
+		opts:   opts,
+		events: make(chan Event),
+		errors: make(chan error, 1),
+		lastID: -1,
+	}
+	w.Events = w.events
+	w.Errors = w.errors
+
+	if opts.CheckpointFile != "" {
+		b, err := os.ReadFile(opts.CheckpointFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error reading event checkpoint: %w", err)
+		}
+		if err == nil {
+			w.lastID, err = strconv.Atoi(strings.TrimSpace(string(b)))
+			if err != nil {
+				return nil, fmt.Errorf("error parsing event checkpoint %s: %w", opts.CheckpointFile, err)
+			}
+		}
+	}
+	return w, nil
+}
+
+// LastID returns the ID of the last delivered event. It must not be called
+// while Run is running.
+func (w *EventWatcher) LastID() int {
+	return w.lastID
+}
+
+// Run polls for events until the context is done, then closes the Events
+// and Errors channels
+func (w *EventWatcher) Run(ctx context.Context) error {
+	defer close(w.events)
+	defer close(w.errors)
+
+	ticker := time.NewTicker(w.opts.pollInterval())
+	defer ticker.Stop()
+	for {
+		err := w.poll(ctx)
+		if err != nil && ctx.Err() == nil {
+			select {
+			case w.errors <- err:
+			default:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll delivers the events newer than the last delivered one
+func (w *EventWatcher) poll(ctx context.Context) error {
+	filter := w.opts.Filter
+	filter.BeforeID = ""
+	filter.Before = time.Time{}
+	filter.Limit = 0
+
+	if w.lastID < 0 {
+		page, err := w.client.getEventsPage(filter)
+		if err != nil {
+			return err
+		}
+		w.lastID = 0
+		if len(page) > 0 {
+			w.lastID, err = eventID(page[0])
+			if err != nil {
+				return err
+			}
+		}
+		return w.checkpoint()
+	}
+
+	// pages are newest first, collect until an already delivered event shows up
+	var newEvents []Event
+	for {
+		page, err := w.client.getEventsPage(filter)
+		if err != nil {
+			return err
+		}
+		done := len(page) < eventsPageSize
+		for _, event := range page {
+			id, err := eventID(event)
+			if err != nil {
+				return err
+			}
+			if id <= w.lastID {
+				done = true
+				break
+			}
+			newEvents = append(newEvents, event)
+		}
+		if done {
+			break
+		}
+		filter.BeforeID = page[len(page)-1].ID
+	}
+
+	for i := len(newEvents) - 1; i >= 0; i-- {
+		select {
+		case w.events <- newEvents[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		w.lastID, _ = eventID(newEvents[i])
+		err := w.checkpoint()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkpoint atomically writes the last delivered event ID to the checkpoint file
+func (w *EventWatcher) checkpoint() error {
+	if w.opts.CheckpointFile == "" {
+		return nil
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(w.opts.CheckpointFile), ".events-checkpoint-")
+	if err != nil {
+		return fmt.Errorf("error writing event checkpoint: %w", err)
+	}
+	_, err = tmp.WriteString(strconv.Itoa(w.lastID) + "\n")
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), w.opts.CheckpointFile)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("error writing event checkpoint: %w", err)
+	}
+	return nil
+}
+
+func eventID(event Event) (int, error) {
+	id, err := strconv.Atoi(event.ID)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing event ID %q: %w", event.ID, err)
+	}
+	return id, nil
+}
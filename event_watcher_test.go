@@ -0,0 +1,174 @@
+package gogobosh_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("EventWatcher", func() {
+	var client *Client
+	var lock sync.Mutex
+	var newestID int
+	var failing bool
+
+	addEvents := func(n int) {
+		lock.Lock()
+		defer lock.Unlock()
+		newestID += n
+	}
+
+	setFailing := func(f bool) {
+		lock.Lock()
+		defer lock.Unlock()
+		failing = f
+	}
+
+	receive := func(w *EventWatcher, n int) []string {
+		var ids []string
+		for i := 0; i < n; i++ {
+			var event Event
+			Eventually(w.Events).Should(Receive(&event))
+			ids = append(ids, event.ID)
+		}
+		return ids
+	}
+
+	BeforeEach(func() {
+		setup("basic")
+		newestID = 3
+		failing = false
+		// events with IDs 1 to newestID, returned newest first in pages of 200
+		mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			defer lock.Unlock()
+			if failing {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			newest := newestID
+			if beforeID := r.URL.Query().Get("before_id"); beforeID != "" {
+				id, _ := strconv.Atoi(beforeID)
+				newest = id - 1
+			}
+			page := []Event{}
+			for id := newest; id > 0 && len(page) < 200; id-- {
+				page = append(page, Event{ID: strconv.Itoa(id), Action: "update"})
+			}
+			_ = json.NewEncoder(w).Encode(page)
+		})
+		config := &Config{
+			BOSHAddress: server.URL,
+			Username:    "admin",
+			Password:    "admin",
+		}
+
+		client, _ = NewClient(config)
+	})
+
+	AfterEach(func() {
+		teardown()
+	})
+
+	run := func(w *EventWatcher) (context.CancelFunc, chan error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- w.Run(ctx)
+		}()
+		return cancel, done
+	}
+
+	It("starts after the newest event and delivers new events in order", func() {
+		w, err := client.NewEventWatcher(EventWatcherOptions{PollInterval: 10 * time.Millisecond})
+		Expect(err).Should(BeNil())
+		cancel, done := run(w)
+
+		Consistently(w.Events, 50*time.Millisecond).ShouldNot(Receive())
+		addEvents(2)
+		Expect(receive(w, 2)).Should(Equal([]string{"4", "5"}))
+		Consistently(w.Events, 50*time.Millisecond).ShouldNot(Receive())
+
+		cancel()
+		Eventually(done).Should(Receive(Equal(context.Canceled)))
+		Expect(w.LastID()).Should(Equal(5))
+	})
+
+	It("resumes from a checkpoint file", func() {
+		dir, err := os.MkdirTemp("", "gogobosh-events")
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = os.RemoveAll(dir) }()
+		path := filepath.Join(dir, "events.checkpoint")
+		Expect(os.WriteFile(path, []byte("1\n"), 0644)).To(Succeed())
+
+		w, err := client.NewEventWatcher(EventWatcherOptions{PollInterval: 10 * time.Millisecond, CheckpointFile: path})
+		Expect(err).Should(BeNil())
+		cancel, done := run(w)
+		Expect(receive(w, 2)).Should(Equal([]string{"2", "3"}))
+		cancel()
+		Eventually(done).Should(Receive())
+
+		Expect(os.ReadFile(path)).Should(Equal([]byte("3\n")))
+
+		addEvents(1)
+		w, err = client.NewEventWatcher(EventWatcherOptions{PollInterval: 10 * time.Millisecond, CheckpointFile: path})
+		Expect(err).Should(BeNil())
+		cancel, done = run(w)
+		Expect(receive(w, 1)).Should(Equal([]string{"4"}))
+		cancel()
+		Eventually(done).Should(Receive())
+	})
+
+	It("pages back to the last delivered event", func() {
+		dir, err := os.MkdirTemp("", "gogobosh-events")
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = os.RemoveAll(dir) }()
+		path := filepath.Join(dir, "events.checkpoint")
+		Expect(os.WriteFile(path, []byte("3"), 0644)).To(Succeed())
+		addEvents(250)
+
+		w, err := client.NewEventWatcher(EventWatcherOptions{PollInterval: 10 * time.Millisecond, CheckpointFile: path})
+		Expect(err).Should(BeNil())
+		cancel, done := run(w)
+		ids := receive(w, 250)
+		Expect(ids[0]).Should(Equal("4"))
+		Expect(ids[249]).Should(Equal("253"))
+		cancel()
+		Eventually(done).Should(Receive())
+	})
+
+	It("reports errors and keeps polling while the director is down", func() {
+		w, err := client.NewEventWatcher(EventWatcherOptions{PollInterval: 10 * time.Millisecond})
+		Expect(err).Should(BeNil())
+		cancel, done := run(w)
+
+		Consistently(w.Events, 50*time.Millisecond).ShouldNot(Receive())
+		setFailing(true)
+		addEvents(1)
+		Eventually(w.Errors).Should(Receive(HaveOccurred()))
+		setFailing(false)
+		Expect(receive(w, 1)).Should(Equal([]string{"4"}))
+
+		cancel()
+		Eventually(done).Should(Receive())
+	})
+
+	It("refuses an unreadable checkpoint", func() {
+		dir, err := os.MkdirTemp("", "gogobosh-events")
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = os.RemoveAll(dir) }()
+		path := filepath.Join(dir, "events.checkpoint")
+		Expect(os.WriteFile(path, []byte("not-an-id"), 0644)).To(Succeed())
+		_, err = client.NewEventWatcher(EventWatcherOptions{CheckpointFile: path})
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,106 @@
+package gogobosh
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// eventsPageSize is the number of events the director returns per request
+const eventsPageSize = 200
+
+// EventsFilter selects director events. Empty fields are not filtered on.
+type EventsFilter struct {
+	// BeforeID only returns events older than the event with this ID
+	BeforeID string
+	// Before and After bound the event timestamps
+	Before     time.Time
+	After      time.Time
+	Deployment string
+	Task       string
+	Instance   string
+	User       string
+	Action     string
+	ObjectType string
+	ObjectName string
+	// Limit is the maximum number of events to return, 0 returns all of them
+	Limit int
+}
+
+func (f EventsFilter) query() url.Values {
+	query := url.Values{}
+	if f.BeforeID != "" {
+		query.Set("before_id", f.BeforeID)
+	}
+	if !f.Before.IsZero() {
+		query.Set("before_time", strconv.FormatInt(f.Before.Unix(), 10))
+	}
+	if !f.After.IsZero() {
+		query.Set("after_time", strconv.FormatInt(f.After.Unix(), 10))
+	}
+	if f.Deployment != "" {
+		query.Set("deployment", f.Deployment)
+	}
+	if f.Task != "" {
+		query.Set("task", f.Task)
+	}
+	if f.Instance != "" {
+		query.Set("instance", f.Instance)
+	}
+	if f.User != "" {
+		query.Set("user", f.User)
+	}
+	if f.Action != "" {
+		query.Set("action", f.Action)
+	}
+	if f.ObjectType != "" {
+		query.Set("object_type", f.ObjectType)
+	}
+	if f.ObjectName != "" {
+		query.Set("object_name", f.ObjectName)
+	}
+	return query
+}
+
+// GetEvents returns the director events matching the filter, newest first.
+// Events are paged through with before_id until the filter's limit is
+// reached or no older events are left.
+func (c *Client) GetEvents(filter EventsFilter) ([]Event, error) {
+	events := []Event{}
+	for {
+		page, err := c.getEventsPage(filter)
+		if err != nil {
+			return []Event{}, err
+		}
+		events = append(events, page...)
+		if filter.Limit > 0 && len(events) >= filter.Limit {
+			return events[:filter.Limit], nil
+		}
+		if len(page) < eventsPageSize {
+			return events, nil
+		}
+		filter.BeforeID = page[len(page)-1].ID
+	}
+}
+
+func (c *Client) getEventsPage(filter EventsFilter) ([]Event, error) {
+	r := c.NewRequest("GET", "/events?"+filter.query().Encode())
+	var events []Event
+	err := c.DoRequestAndUnmarshal(r, &events)
+	if err != nil {
+		return []Event{}, fmt.Errorf("error getting events: %w", err)
+	}
+	return events, nil
+}
+
+// GetEvent returns the director event with the given ID
+func (c *Client) GetEvent(id string) (Event, error) {
+	r := c.NewRequest("GET", "/events/"+id)
+	var event Event
+	err := c.DoRequestAndUnmarshal(r, &event)
+	if err != nil {
+		return Event{}, fmt.Errorf("error getting event %s: %w", id, err)
+	}
+	return event, nil
+}
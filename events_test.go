@@ -0,0 +1,135 @@
+package gogobosh_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Events", func() {
+	Describe("Test events", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"GET", "/events", events, ""},
+				{"GET", "/events/1042", event, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("can get events", func() {
+			events, err := client.GetEvents(EventsFilter{})
+			Expect(err).Should(BeNil())
+			Expect(events).Should(HaveLen(2))
+			Expect(events[0].ID).Should(Equal("1042"))
+			Expect(events[0].ParentID).Should(Equal("1041"))
+			Expect(events[0].ObjectType).Should(Equal("deployment"))
+			Expect(events[0].Context).Should(HaveKey("after"))
+			Expect(receivedRequests["GET /events"].Query).Should(BeEmpty())
+		})
+
+		It("passes filters to the director", func() {
+			_, err := client.GetEvents(EventsFilter{
+				BeforeID:   "2000",
+				Before:     time.Unix(1659700000, 0),
+				After:      time.Unix(1659600000, 0),
+				Deployment: "cf",
+				Task:       "312",
+				Instance:   "router/0",
+				User:       "admin",
+				Action:     "update",
+				ObjectType: "deployment",
+				ObjectName: "cf",
+			})
+			Expect(err).Should(BeNil())
+			query := receivedRequests["GET /events"].Query
+			Expect(query).Should(HaveKeyWithValue("before_id", []string{"2000"}))
+			Expect(query).Should(HaveKeyWithValue("before_time", []string{"1659700000"}))
+			Expect(query).Should(HaveKeyWithValue("after_time", []string{"1659600000"}))
+			Expect(query).Should(HaveKeyWithValue("deployment", []string{"cf"}))
+			Expect(query).Should(HaveKeyWithValue("task", []string{"312"}))
+			Expect(query).Should(HaveKeyWithValue("instance", []string{"router/0"}))
+			Expect(query).Should(HaveKeyWithValue("user", []string{"admin"}))
+			Expect(query).Should(HaveKeyWithValue("action", []string{"update"}))
+			Expect(query).Should(HaveKeyWithValue("object_type", []string{"deployment"}))
+			Expect(query).Should(HaveKeyWithValue("object_name", []string{"cf"}))
+		})
+
+		It("can get an event", func() {
+			event, err := client.GetEvent("1042")
+			Expect(err).Should(BeNil())
+			Expect(event.ID).Should(Equal("1042"))
+			Expect(event.Task).Should(Equal("312"))
+			Expect(event.Timestamp).Should(Equal(1659657425))
+		})
+	})
+
+	Describe("Test events pagination", func() {
+		var client *Client
+		var beforeIDs []string
+
+		BeforeEach(func() {
+			setup("basic")
+			beforeIDs = nil
+			// 300 events with IDs 1 to 300, returned newest first in pages of 200
+			mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+				newest := 300
+				beforeID := r.URL.Query().Get("before_id")
+				beforeIDs = append(beforeIDs, beforeID)
+				if beforeID != "" {
+					id, _ := strconv.Atoi(beforeID)
+					newest = id - 1
+				}
+				page := []Event{}
+				for id := newest; id > 0 && len(page) < 200; id-- {
+					page = append(page, Event{ID: strconv.Itoa(id), Action: "update"})
+				}
+				_ = json.NewEncoder(w).Encode(page)
+			})
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("pages through all events", func() {
+			events, err := client.GetEvents(EventsFilter{})
+			Expect(err).Should(BeNil())
+			Expect(events).Should(HaveLen(300))
+			Expect(events[0].ID).Should(Equal("300"))
+			Expect(events[299].ID).Should(Equal("1"))
+			Expect(beforeIDs).Should(Equal([]string{"", "101"}))
+		})
+
+		It("stops paging at the limit", func() {
+			events, err := client.GetEvents(EventsFilter{Limit: 150})
+			Expect(err).Should(BeNil())
+			Expect(events).Should(HaveLen(150))
+			Expect(events[149].ID).Should(Equal("151"))
+			Expect(beforeIDs).Should(HaveLen(1))
+		})
+	})
+})
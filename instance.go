@@ -0,0 +1,114 @@
+package gogobosh
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// IgnoreInstance excludes the instance from deploys, cloud checks and
+// resurrection until it is unignored. It returns the VM of the instance as
+// reported by the director afterwards.
+func (c *Client) IgnoreInstance(deployment, instanceGroup, instanceID string) (VM, error) {
+	return c.setInstanceIgnore(deployment, instanceGroup, instanceID, true)
+}
+
+// UnignoreInstance makes the director manage an ignored instance again. It
+// returns the VM of the instance as reported by the director afterwards.
+func (c *Client) UnignoreInstance(deployment, instanceGroup, instanceID string) (VM, error) {
+	return c.setInstanceIgnore(deployment, instanceGroup, instanceID, false)
+}
+
+func (c *Client) setInstanceIgnore(deployment, instanceGroup, instanceID string, ignore bool) (VM, error) {
+	r := c.NewRequest("PUT", fmt.Sprintf("/deployments/%s/instance_groups/%s/%s/ignore",
+		deployment, instanceGroup, instanceID))
+	in := struct {
+		Ignore bool `json:"ignore"`
+	}{
+		Ignore: ignore,
+	}
+	b, err := json.Marshal(&in)
+	if err != nil {
+		return VM{}, fmt.Errorf("error marshalling the ignore request: %w", err)
+	}
+	r.body = bytes.NewBuffer(b)
+	r.header["Content-Type"] = "application/json"
+
+	resp, err := c.DoRequest(r)
+	if err != nil {
+		return VM{}, fmt.Errorf("error setting ignore to %t on instance %s/%s: %w", ignore, instanceGroup, instanceID, err)
+	}
+	_ = resp.Body.Close()
+
+	return c.getInstanceVM(deployment, instanceGroup, instanceID)
+}
+
+// SetResurrection enables or disables the resurrector for the whole director.
+// The director does not report the global setting back, per instance state
+// is returned by SetInstanceResurrection.
+func (c *Client) SetResurrection(enabled bool) error {
+	err := c.setResurrectionPaused("/resurrection", !enabled)
+	if err != nil {
+		return fmt.Errorf("error setting director resurrection to %t: %w", enabled, err)
+	}
+	return nil
+}
+
+// SetInstanceResurrection enables or disables the resurrector for a single
+// instance. It returns the VM of the instance as reported by the director afterwards.
+func (c *Client) SetInstanceResurrection(deployment, instanceGroup, instanceID string, enabled bool) (VM, error) {
+	err := c.setResurrectionPaused(fmt.Sprintf("/deployments/%s/jobs/%s/%s/resurrection",
+		deployment, instanceGroup, instanceID), !enabled)
+	if err != nil {
+		return VM{}, fmt.Errorf("error setting resurrection to %t on instance %s/%s: %w", enabled, instanceGroup, instanceID, err)
+	}
+	return c.getInstanceVM(deployment, instanceGroup, instanceID)
+}
+
+func (c *Client) setResurrectionPaused(path string, paused bool) error {
+	r := c.NewRequest("PUT", path)
+	in := struct {
+		ResurrectionPaused bool `json:"resurrection_paused"`
+	}{
+		ResurrectionPaused: paused,
+	}
+	b, err := json.Marshal(&in)
+	if err != nil {
+		return fmt.Errorf("error marshalling the resurrection request: %w", err)
+	}
+	r.body = bytes.NewBuffer(b)
+	r.header["Content-Type"] = "application/json"
+
+	resp, err := c.DoRequest(r)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	return nil
+}
+
+// errInstanceFound stops streaming deployment instances once the wanted one was found
+var errInstanceFound = errors.New("instance found")
+
+// getInstanceVM returns the instance as reported by the full instances
+// listing, which is the only one carrying its ignore and resurrection state.
+// VMCID is empty when the instance currently has no VM.
+func (c *Client) getInstanceVM(deployment, instanceGroup, instanceID string) (VM, error) {
+	var found VM
+	err := c.streamFullDeploymentRows(deployment, "instances", DefaultVMsOptions(), func(vm VM) error {
+		if vm.JobName == instanceGroup && vm.ID == instanceID {
+			found = vm
+			return errInstanceFound
+		}
+		return nil
+	})
+	if errors.Is(err, errInstanceFound) {
+		return found, nil
+	}
+	if err != nil {
+		return VM{}, err
+	}
+	return VM{}, fmt.Errorf("instance %s/%s not found in deployment %s", instanceGroup, instanceID, deployment)
+}
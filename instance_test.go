@@ -0,0 +1,83 @@
+package gogobosh_test
+
+import (
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Instance", func() {
+	Describe("Test instance management", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"PUT", "/deployments/cf-warden/instance_groups/doppler_z1/4a9278c8/ignore", "", ""},
+				{"PUT", "/deployments/cf-warden/instance_groups/doppler_z1/9f0c2d4b/ignore", "", ""},
+				{"PUT", "/deployments/cf-warden/jobs/doppler_z1/4a9278c8/resurrection", "", ""},
+				{"PUT", "/resurrection", "", ""},
+				{"GET", "/deployments/cf-warden/instances", "", "/tasks/2"},
+				{"GET", "/tasks/2", task, ""},
+				{"GET", "/tasks/2/output", instanceStates, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("can ignore an instance", func() {
+			vm, err := client.IgnoreInstance("cf-warden", "doppler_z1", "4a9278c8")
+			Expect(err).Should(BeNil())
+			req := receivedRequests["PUT /deployments/cf-warden/instance_groups/doppler_z1/4a9278c8/ignore"]
+			Expect(req.Body).Should(MatchJSON(`{"ignore": true}`))
+			Expect(receivedRequests["GET /deployments/cf-warden/instances"].Query).Should(HaveKeyWithValue("format", []string{"full"}))
+			Expect(vm.ID).Should(Equal("4a9278c8"))
+			Expect(vm.VMCID).Should(Equal("ec974048-3352-4ba4-669d-beab87b16bcb"))
+			Expect(vm.Ignore).Should(BeTrue())
+		})
+
+		It("returns the state of an instance without a VM", func() {
+			vm, err := client.IgnoreInstance("cf-warden", "doppler_z1", "9f0c2d4b")
+			Expect(err).Should(BeNil())
+			Expect(vm.ID).Should(Equal("9f0c2d4b"))
+			Expect(vm.VMCID).Should(BeEmpty())
+			Expect(vm.State).Should(Equal("detached"))
+			Expect(vm.Ignore).Should(BeTrue())
+		})
+
+		It("can unignore an instance", func() {
+			_, err := client.UnignoreInstance("cf-warden", "doppler_z1", "4a9278c8")
+			Expect(err).Should(BeNil())
+			req := receivedRequests["PUT /deployments/cf-warden/instance_groups/doppler_z1/4a9278c8/ignore"]
+			Expect(req.Body).Should(MatchJSON(`{"ignore": false}`))
+		})
+
+		It("can pause resurrection of an instance", func() {
+			vm, err := client.SetInstanceResurrection("cf-warden", "doppler_z1", "4a9278c8", false)
+			Expect(err).Should(BeNil())
+			req := receivedRequests["PUT /deployments/cf-warden/jobs/doppler_z1/4a9278c8/resurrection"]
+			Expect(req.Body).Should(MatchJSON(`{"resurrection_paused": true}`))
+			Expect(vm.ID).Should(Equal("4a9278c8"))
+			Expect(vm.ResurrectionPaused).Should(BeTrue())
+		})
+
+		It("can enable resurrection on the director", func() {
+			err := client.SetResurrection(true)
+			Expect(err).Should(BeNil())
+			Expect(receivedRequests["PUT /resurrection"].Body).Should(MatchJSON(`{"resurrection_paused": false}`))
+		})
+
+		It("fails for unknown instances", func() {
+			_, err := client.IgnoreInstance("cf-warden", "doppler_z1", "unknown")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
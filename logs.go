@@ -0,0 +1,150 @@
+package gogobosh
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// Log types accepted by FetchLogsOptions
+const (
+	LogsTypeJob    = "job"
+	LogsTypeAgent  = "agent"
+	LogsTypeSystem = "system"
+)
+
+// FetchLogsOptions configures which logs are fetched from an instance
+type FetchLogsOptions struct {
+	// Type is one of LogsTypeJob, LogsTypeAgent or LogsTypeSystem, defaults to LogsTypeJob
+	Type string
+	// Filters restricts job logs to the given job names
+	Filters []string
+	// TaskWaitOptions bounds the wait for the fetch logs task, which defaults to 10 minutes
+	TaskWaitOptions
+}
+
+// FetchLogs bundles the logs of the instance into a tarball and streams it
+// into w. An empty instanceID fetches the logs of every instance in the
+// group, and an empty instanceGroup those of the whole deployment.
+func (c *Client) FetchLogs(deployment, instanceGroup, instanceID string, opts FetchLogsOptions, w io.Writer) (Task, error) {
+	if instanceGroup == "" {
+		instanceGroup = "*"
+	}
+	if instanceID == "" {
+		instanceID = "*"
+	}
+	query := url.Values{}
+	query.Set("type", LogsTypeJob)
+	if opts.Type != "" {
+		query.Set("type", opts.Type)
+	}
+	if len(opts.Filters) > 0 {
+		query.Set("filters", strings.Join(opts.Filters, ","))
+	}
+
+	r := c.NewRequest("PUT", fmt.Sprintf("/deployments/%s/jobs/%s/%s/logs?%s",
+		deployment, instanceGroup, instanceID, query.Encode()))
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error fetching logs of %s/%s: %w", instanceGroup, instanceID, err)
+	}
+
+	task, err = c.WaitUntilDoneWithInterval(task, opts.timeout(time.Minute*10), opts.pollInterval())
+	if err != nil {
+		return task, fmt.Errorf("error waiting for fetch logs task to complete: %w", err)
+	}
+
+	err = c.DownloadResource(context.Background(), task.Result, w)
+	if err != nil {
+		return task, fmt.Errorf("error downloading logs of %s/%s: %w", instanceGroup, instanceID, err)
+	}
+	return task, nil
+}
+
+// FetchLogsToDir fetches the logs like FetchLogs and unpacks the tarball into dir
+func (c *Client) FetchLogsToDir(deployment, instanceGroup, instanceID string, opts FetchLogsOptions, dir string) (Task, error) {
+	pr, pw := io.Pipe()
+	extracted := make(chan error, 1)
+	go func() {
+		err := extractTarball(pr, dir)
+		// Drain whatever is left so the download is not blocked on a failed extraction
+		_, _ = io.Copy(io.Discard, pr)
+		extracted <- err
+	}()
+
+	task, err := c.FetchLogs(deployment, instanceGroup, instanceID, opts, pw)
+	_ = pw.CloseWithError(err)
+	extractErr := <-extracted
+	if err != nil {
+		return task, err
+	}
+	if extractErr != nil {
+		return task, fmt.Errorf("error unpacking logs into %s: %w", dir, extractErr)
+	}
+	return task, nil
+}
+
+// extractTarball unpacks the gzipped tarball into dir, refusing entries that
+// would be written outside of it
+func extractTarball(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = gz.Close() }()
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(root, filepath.FromSlash(hdr.Name))
+		if target != root && !strings.HasPrefix(target, root+string(os.PathSeparator)) {
+			return fmt.Errorf("tarball entry %s is outside of %s", hdr.Name, dir)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeReg:
+			err = writeFile(target, tr, hdr.FileInfo().Mode().Perm())
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader, perm os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
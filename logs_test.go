@@ -0,0 +1,95 @@
+package gogobosh_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func logsTarball(files map[string]string) string {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		Expect(tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})).To(Succeed())
+		_, err := tw.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(tw.Close()).To(Succeed())
+	Expect(gz.Close()).To(Succeed())
+	return buf.String()
+}
+
+var _ = Describe("Logs", func() {
+	Describe("Test fetch logs", func() {
+		var (
+			client  *Client
+			tarball string
+		)
+
+		BeforeEach(func() {
+			tarball = logsTarball(map[string]string{
+				"./doppler/doppler.stdout.log": "started doppler\n",
+			})
+			setupMockRoutes([]MockRoute{
+				{"PUT", "/deployments/cf-warden/jobs/doppler_z1/4a9278c8/logs", "", "/tasks/7"},
+				{"GET", "/tasks/7", fetchLogsTask, ""},
+				{"GET", "/resources/9d7e5a42-7f3a-4c1b-8e6d-2a1b3c4d5e6f", tarball, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("can stream the logs tarball", func() {
+			var out bytes.Buffer
+			task, err := client.FetchLogs("cf-warden", "doppler_z1", "4a9278c8", FetchLogsOptions{
+				Type:            LogsTypeAgent,
+				Filters:         []string{"doppler", "metron_agent"},
+				TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10},
+			}, &out)
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(7))
+			Expect(out.String()).Should(Equal(tarball))
+
+			req := receivedRequests["PUT /deployments/cf-warden/jobs/doppler_z1/4a9278c8/logs"]
+			Expect(req.Query["type"]).Should(Equal([]string{"agent"}))
+			Expect(req.Query["filters"]).Should(Equal([]string{"doppler,metron_agent"}))
+		})
+
+		It("can unpack the logs into a directory", func() {
+			dir, err := os.MkdirTemp("", "gogobosh-logs")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			_, err = client.FetchLogsToDir("cf-warden", "doppler_z1", "4a9278c8", FetchLogsOptions{
+				TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10},
+			}, dir)
+			Expect(err).Should(BeNil())
+
+			b, err := os.ReadFile(filepath.Join(dir, "doppler", "doppler.stdout.log"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).Should(Equal("started doppler\n"))
+		})
+	})
+})
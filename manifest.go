@@ -0,0 +1,149 @@
+package gogobosh
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DeploymentManifest is the typed form of the parts of a deployment manifest
+// that describe where jobs run
+type DeploymentManifest struct {
+	Name           string             `yaml:"name"`
+	Releases       []ManifestRelease  `yaml:"releases,omitempty"`
+	Stemcells      []ManifestStemcell `yaml:"stemcells,omitempty"`
+	InstanceGroups []InstanceGroup    `yaml:"instance_groups,omitempty"`
+
+	// Extra keeps the keys that are not modeled, so that a parsed manifest can
+	// be marshalled again without losing them. The nested types keep theirs the same way.
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// ManifestRelease is a release used by a deployment
+type ManifestRelease struct {
+	Name    string                 `yaml:"name"`
+	Version string                 `yaml:"version"`
+	URL     string                 `yaml:"url,omitempty"`
+	SHA1    string                 `yaml:"sha1,omitempty"`
+	Extra   map[string]interface{} `yaml:",inline"`
+}
+
+// ManifestStemcell is a stemcell used by a deployment, referenced by its alias
+type ManifestStemcell struct {
+	Alias   string                 `yaml:"alias"`
+	OS      string                 `yaml:"os,omitempty"`
+	Name    string                 `yaml:"name,omitempty"`
+	Version string                 `yaml:"version"`
+	Extra   map[string]interface{} `yaml:",inline"`
+}
+
+// InstanceGroup of a deployment manifest
+type InstanceGroup struct {
+	Name      string                 `yaml:"name"`
+	Instances int                    `yaml:"instances"`
+	AZs       []string               `yaml:"azs,omitempty"`
+	Lifecycle string                 `yaml:"lifecycle,omitempty"`
+	Stemcell  string                 `yaml:"stemcell,omitempty"`
+	VMType    string                 `yaml:"vm_type,omitempty"`
+	Networks  []InstanceGroupNetwork `yaml:"networks,omitempty"`
+	Jobs      []InstanceGroupJob     `yaml:"jobs,omitempty"`
+
+	PersistentDisk     int                    `yaml:"persistent_disk,omitempty"`
+	PersistentDiskType string                 `yaml:"persistent_disk_type,omitempty"`
+	Extra              map[string]interface{} `yaml:",inline"`
+}
+
+// InstanceGroupNetwork is a network an instance group is placed on
+type InstanceGroupNetwork struct {
+	Name      string                 `yaml:"name"`
+	StaticIPs []string               `yaml:"static_ips,omitempty"`
+	Default   []string               `yaml:"default,omitempty"`
+	Extra     map[string]interface{} `yaml:",inline"`
+}
+
+// InstanceGroupJob is a release job colocated on an instance group
+type InstanceGroupJob struct {
+	Name    string                 `yaml:"name"`
+	Release string                 `yaml:"release"`
+	Extra   map[string]interface{} `yaml:",inline"`
+}
+
+// ParseManifest parses the YAML content of a deployment manifest
+func ParseManifest(content string) (DeploymentManifest, error) {
+	var m DeploymentManifest
+	err := yaml.Unmarshal([]byte(content), &m)
+	if err != nil {
+		return DeploymentManifest{}, fmt.Errorf("error parsing deployment manifest: %w", err)
+	}
+	return m, nil
+}
+
+// Parse parses the manifest returned by GetDeployment
+func (m Manifest) Parse() (DeploymentManifest, error) {
+	return ParseManifest(m.Manifest)
+}
+
+// Marshal renders the manifest as YAML
+func (m DeploymentManifest) Marshal() (string, error) {
+	b, err := yaml.Marshal(&m)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling deployment manifest: %w", err)
+	}
+	return string(b), nil
+}
+
+// ResolveStemcellOS returns a copy of the manifest in which the stemcells
+// given by name rather than by os have the operating system of the director
+// stemcell with that name, as returned by GetStemcells
+func (m DeploymentManifest) ResolveStemcellOS(stemcells []Stemcell) DeploymentManifest {
+	resolved := make([]ManifestStemcell, len(m.Stemcells))
+	copy(resolved, m.Stemcells)
+	for i := range resolved {
+		if resolved[i].OS != "" || resolved[i].Name == "" {
+			continue
+		}
+		for _, stemcell := range stemcells {
+			if stemcell.Name == resolved[i].Name {
+				resolved[i].OS = stemcell.OperatingSystem
+				break
+			}
+		}
+	}
+	m.Stemcells = resolved
+	return m
+}
+
+// needsStemcellOS reports whether a stemcell is given by name only
+func (m DeploymentManifest) needsStemcellOS() bool {
+	for _, stemcell := range m.Stemcells {
+		if stemcell.OS == "" && stemcell.Name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// persistentDisk resolves the disk type, size in MB and cloud properties of
+// the persistent disk of the instance group
+func (ig InstanceGroup) persistentDisk(cc CloudConfig) (string, int, map[string]interface{}) {
+	if ig.PersistentDiskType == "" {
+		return "", ig.PersistentDisk, nil
+	}
+	for _, diskType := range cc.DiskTypes {
+		if diskType.Name == ig.PersistentDiskType {
+			return diskType.Name, diskType.DiskSize, diskType.CloudProperties
+		}
+	}
+	return ig.PersistentDiskType, 0, nil
+}
+
+// stemcellOS returns the operating system of the stemcell with the given
+// alias. Stemcells given by name must be resolved with ResolveStemcellOS first.
+func (m DeploymentManifest) stemcellOS(alias string) string {
+	for _, stemcell := range m.Stemcells {
+		if stemcell.Alias == alias {
+			return stemcell.OS
+		}
+	}
+	return ""
+}
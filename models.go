@@ -51,6 +51,7 @@ type Deployment struct {
 	CloudConfig string     `json:"cloud_config"`
 	Releases    []Resource `json:"releases"`
 	Stemcells   []Resource `json:"stemcells"`
+	Teams       []string   `json:"teams"`
 }
 
 // Resource struct
@@ -83,6 +84,31 @@ type VM struct {
 	ID                 string    `json:"id"`
 	Bootstrap          bool      `json:"bootstrap"`
 	Ignore             bool      `json:"ignore"`
+	DiskCIDs           []string  `json:"disk_cids"`
+}
+
+// basicVM is a VM as returned by the director without format=full. It does
+// not carry the VM type nor the ignore and resurrection state of the instance.
+type basicVM struct {
+	AgentID string   `json:"agent_id"`
+	CID     string   `json:"cid"`
+	Job     string   `json:"job"`
+	Index   int      `json:"index"`
+	ID      string   `json:"id"`
+	AZ      string   `json:"az"`
+	IPs     []string `json:"ips"`
+}
+
+func (b basicVM) toVM() VM {
+	return VM{
+		VMCID:   b.CID,
+		IPs:     b.IPs,
+		AgentID: b.AgentID,
+		JobName: b.Job,
+		Index:   b.Index,
+		AZ:      b.AZ,
+		ID:      b.ID,
+	}
 }
 
 // Vitals for a VM
@@ -193,6 +219,115 @@ type Cfg struct {
 	Name      string `json:"name"`
 	Type      string `json:"type"`
 	Content   string `json:"content"`
-	CreatedAt int    `json:"int"`
+	CreatedAt string `json:"created_at"`
+	Team      string `json:"team"`
+	Current   bool   `json:"current"`
 	Deleted   bool   `json:"deleted"`
 }
+
+// Errand struct
+type Errand struct {
+	Name string `json:"name"`
+}
+
+// InstanceSlug identifies an instance group, or a single instance of it when ID is set
+type InstanceSlug struct {
+	Group string `json:"group"`
+	ID    string `json:"id,omitempty"`
+}
+
+// ErrandResult is the outcome of an errand run on a single instance
+type ErrandResult struct {
+	Instance   InstanceSlug `json:"instance"`
+	ErrandName string       `json:"errand_name"`
+	ExitCode   int          `json:"exit_code"`
+	Stdout     string       `json:"stdout"`
+	Stderr     string       `json:"stderr"`
+	Logs       ErrandLogs   `json:"logs"`
+}
+
+// ErrandLogs references the logs tarball of an errand run
+type ErrandLogs struct {
+	BlobstoreID string `json:"blobstore_id"`
+}
+
+// Problem found in a deployment by a cloud check scan
+type Problem struct {
+	ID          int                    `json:"id"`
+	Type        string                 `json:"type"`
+	Description string                 `json:"description"`
+	Data        map[string]interface{} `json:"data"`
+	Resolutions []ProblemResolution    `json:"resolutions"`
+}
+
+// ProblemResolution is a way a Problem can be resolved
+type ProblemResolution struct {
+	Name string `json:"name"`
+	Plan string `json:"plan"`
+}
+
+// SSHHost is an instance on which the director set up SSH access
+type SSHHost struct {
+	Status        string `json:"status"`
+	IP            string `json:"ip"`
+	HostPublicKey string `json:"host_public_key"`
+	Job           string `json:"job"`
+	Index         int    `json:"index"`
+	ID            string `json:"id"`
+}
+
+// OrphanedDisk is a persistent disk kept by the director after its instance was deleted
+type OrphanedDisk struct {
+	DiskCID         string                 `json:"disk_cid"`
+	Size            int                    `json:"size"`
+	AZ              string                 `json:"az"`
+	Deployment      string                 `json:"deployment_name"`
+	Instance        string                 `json:"instance_name"`
+	CloudProperties map[string]interface{} `json:"cloud_properties"`
+	OrphanedAt      string                 `json:"orphaned_at"`
+}
+
+// InstanceDisks lists the persistent disks attached to an instance
+type InstanceDisks struct {
+	InstanceGroup string
+	ID            string
+	AZ            string
+	DiskCIDs      []string
+	// DiskType is the cloud config disk type of the instance group, if it uses one
+	DiskType string
+	// Size of the persistent disk in MB
+	Size            int
+	CloudProperties map[string]interface{}
+}
+
+// OrphanedVM is a VM the director kept around after a failed update
+type OrphanedVM struct {
+	AZ         string   `json:"az"`
+	CID        string   `json:"cid"`
+	Deployment string   `json:"deployment_name"`
+	Instance   string   `json:"instance_name"`
+	IPs        []string `json:"ip_addresses"`
+	OrphanedAt string   `json:"orphaned_at"`
+}
+
+// Snapshot of the persistent disk of an instance
+type Snapshot struct {
+	Job         string `json:"job"`
+	Index       int    `json:"index"`
+	ID          string `json:"uuid"`
+	SnapshotCID string `json:"snapshot_cid"`
+	CreatedAt   string `json:"created_at"`
+	Clean       bool   `json:"clean"`
+}
+
+// ConfigDiff is the line by line difference between two config versions
+type ConfigDiff struct {
+	Lines []ConfigDiffLine `json:"diff"`
+}
+
+// ConfigDiffLine is a line of a ConfigDiff. State is "added", "removed" or
+// empty for the unchanged lines given as context.
+type ConfigDiffLine struct {
+	Text  string
+	State string
+}
@@ -68,6 +68,31 @@ const task = `{
 
 const vms = `{"vm_cid":"ec974048-3352-4ba4-669d-beab87b16bcb","disk_cid":null,"ips":["10.244.0.142"],"dns":[],"agent_id":"c5e7c705-459e-41c0-b640-db32d8dc6e71","job_name":"doppler_z1","index":0,"job_state":"running","state":"started","resource_pool":"medium_z1","vm_type":"default","vitals":{"cpu":{"sys":"9.1","user":"2.1","wait":"1.7"},"disk":{"ephemeral":{"inode_percent":"11","percent":"36"},"persistent":{"inode_percent":"11","percent":"36"},"system":{"inode_percent":"11","percent":"36"}},"load":["0.61","0.74","1.10"],"mem":{"kb":"2520960","percent":"41"},"swap":{"kb":"102200","percent":"10"}},"processes":[{"name":"doppler","state":"running","uptime":{"secs":11794845},"mem":{"kb":2252,"percent":16.5},"cpu":{"total":0.9}},{"name":"syslog_drain_binder","state":"running","uptime":{"secs":11794845},"mem":{"kb":2252,"percent":16.5},"cpu":{"total":0.9}},{"name":"metron_agent","state":"running","uptime":{"secs":11794845},"mem":{"kb":2252,"percent":16.5},"cpu":{"total":0.9}}],"resurrection_paused":false,"az":"z1","id":"4a9278c8-e93a-4d6a-b22c-13560208da9e","bootstrap":true,"ignore":false}`
 
+const basicVMs = `[
+  {
+    "agent_id": "c5e7c705-459e-41c0-b640-db32d8dc6e71",
+    "cid": "ec974048-3352-4ba4-669d-beab87b16bcb",
+    "job": "doppler_z1",
+    "index": 0,
+    "id": "4a9278c8-e93a-4d6a-b22c-13560208da9e",
+    "az": "z1",
+    "ips": ["10.244.0.142"],
+    "vm_created_at": "2022-08-03T22:57:02Z",
+    "active": true
+  },
+  {
+    "agent_id": "2c7ac1a6-5e3a-4b89-8c1e-3f41d9b0c6f2",
+    "cid": "5c8f3a2e-6d1b-4f7e-9a0c-2b4d6e8f1a3c",
+    "job": "doppler_z1",
+    "index": 1,
+    "id": "9f0c2d4b-7a13-4c8e-b5d6-1e2f3a4b5c6d",
+    "az": "z2",
+    "ips": ["10.244.0.146"],
+    "vm_created_at": "2022-08-03T22:57:04Z",
+    "active": true
+  }
+]`
+
 const task3 = `{
     "id": 3,
     "state": "done",
@@ -79,3 +104,660 @@ const task3 = `{
     "deployment": "deployment-foo",
     "context_id": ""
 }`
+
+const errands = `[
+  {"name": "smoke_tests"},
+  {"name": "acceptance_tests"}
+]`
+
+const errandTask = `{
+  "id": 5,
+  "state": "done",
+  "description": "run errand smoke_tests from deployment cf-warden",
+  "result": "",
+  "user": "admin"
+}`
+
+const errandResults = `{"instance":{"group":"smoke_tests","id":"1b5a4e6c-2f5d-4d3e-9b8a-7c6d5e4f3a2b"},"errand_name":"smoke_tests","exit_code":0,"stdout":"all tests passed\n","stderr":"","logs":{"blobstore_id":"6b1ec4ab-a7d8-4d0b-8d76-b9e5d5d4b8d1"}}
+{"instance":{"group":"smoke_tests","id":"8e7d6c5b-4a39-4281-b0c1-d2e3f4a5b6c7"},"errand_name":"smoke_tests","exit_code":1,"stdout":"","stderr":"1 test failed\n","logs":{"blobstore_id":"b1e5c7d0-3f2a-4c6e-9d8b-a0f1e2d3c4b5"}}
+`
+
+const cloudCheckTask = `{
+  "id": 6,
+  "state": "done",
+  "description": "scan cloud",
+  "result": "scan complete",
+  "user": "admin"
+}`
+
+const problems = `[
+  {
+    "id": 4,
+    "type": "unresponsive_agent",
+    "description": "VM for 'doppler_z1/4a9278c8 (0)' with cloud ID 'ec974048' is not responding.",
+    "data": {"agent_id": "c5e7c705-459e-41c0-b640-db32d8dc6e71", "instance_id": 12},
+    "resolutions": [
+      {"name": "ignore", "plan": "Skip for now"},
+      {"name": "reboot_vm", "plan": "Reboot VM"},
+      {"name": "recreate_vm", "plan": "Recreate VM and wait for processes to start"},
+      {"name": "delete_vm", "plan": "Delete VM"},
+      {"name": "delete_vm_reference", "plan": "Delete VM reference"}
+    ]
+  },
+  {
+    "id": 5,
+    "type": "inactive_disk",
+    "description": "Disk 'disk-3f2a' (router_z1/0, 1024M) is inactive",
+    "data": {"disk_id": 7},
+    "resolutions": [
+      {"name": "ignore", "plan": "Skip for now"},
+      {"name": "delete_disk", "plan": "Delete disk"},
+      {"name": "activate_disk", "plan": "Activate disk"}
+    ]
+  }
+]`
+
+const fetchLogsTask = `{
+  "id": 7,
+  "state": "done",
+  "description": "fetch logs",
+  "result": "9d7e5a42-7f3a-4c1b-8e6d-2a1b3c4d5e6f",
+  "user": "admin"
+}`
+
+const sshTask = `{
+  "id": 8,
+  "state": "done",
+  "description": "ssh: setup:{\"ids\"=>[\"4a9278c8\"], \"indexes\"=>[\"4a9278c8\"], \"job\"=>\"doppler_z1\"}",
+  "result": "",
+  "user": "admin"
+}`
+
+const sshHostsTemplate = `[{"command":"setup","status":"success","ip":"%s","host_public_key":"%s","index":0,"job":"doppler_z1","id":"4a9278c8"}]`
+
+const orphanedDisks = `[
+  {
+    "disk_cid": "disk-3f2a9c1e-5b7d-4e8f-a0b1-c2d3e4f5a6b7",
+    "size": 10240,
+    "az": "z1",
+    "deployment_name": "cf-warden",
+    "instance_name": "database/2b8f6c1d-9e3a-4f5b-8c7d-6e5f4a3b2c1d",
+    "cloud_properties": {"type": "gp2"},
+    "orphaned_at": "2022-08-03 22:57:02 UTC"
+  }
+]`
+
+const diskTask = `{
+  "id": 9,
+  "state": "queued",
+  "description": "attach disk",
+  "result": "",
+  "user": "admin"
+}`
+
+const instances = `{"vm_cid":"ec974048-3352-4ba4-669d-beab87b16bcb","disk_cid":"disk-8a7b6c5d","disk_cids":["disk-8a7b6c5d"],"ips":["10.244.0.150"],"job_name":"database","index":0,"job_state":"running","state":"started","az":"z1","id":"2b8f6c1d-9e3a-4f5b-8c7d-6e5f4a3b2c1d","bootstrap":true,"ignore":false}
+{"vm_cid":null,"disk_cid":"disk-1a2b3c4d","disk_cids":["disk-1a2b3c4d"],"ips":[],"job_name":"database","index":1,"job_state":null,"state":"detached","az":"z2","id":"7c6d5e4f-3a2b-4c1d-9e8f-0a1b2c3d4e5f","bootstrap":false,"ignore":false}
+`
+
+const diskManifest = `{
+  "manifest": "name: cf-warden\ninstance_groups:\n- name: database\n  instances: 2\n  azs: [z1, z2]\n  persistent_disk_type: large\n- name: broker\n  instances: 1\n  azs: [z1]\n  persistent_disk: 1024\n"
+}`
+
+const diskCloudConfigs = `[
+  {
+    "id": "22",
+    "name": "default",
+    "type": "cloud",
+    "content": "disk_types:\n- name: default\n  disk_size: 3072\n- name: large\n  disk_size: 10240\n  cloud_properties:\n    type: gp2\n",
+    "created_at": "2022-08-06 09:30:00 UTC",
+    "team": null,
+    "current": true
+  }
+]`
+
+const orphanedVMs = `[
+  {
+    "az": "z1",
+    "cid": "ec974048-3352-4ba4-669d-beab87b16bcb",
+    "deployment_name": "cf-warden",
+    "instance_name": "doppler_z1/4a9278c8-e93a-4d6a-b22c-13560208da9e",
+    "ip_addresses": ["10.244.0.142"],
+    "orphaned_at": "2022-08-03 22:57:02 UTC"
+  },
+  {
+    "az": "z2",
+    "cid": "0d1e2f3a-4b5c-6d7e-8f9a-b0c1d2e3f4a5",
+    "deployment_name": "cf-warden",
+    "instance_name": "doppler_z1/9f0c2d4b-7a13-4c8e-b5d6-1e2f3a4b5c6d",
+    "ip_addresses": ["10.244.0.150"],
+    "orphaned_at": "2022-08-03 22:58:02 UTC"
+  },
+  {
+    "az": "z1",
+    "cid": "6a5b4c3d-2e1f-0a9b-8c7d-6e5f4a3b2c1d",
+    "deployment_name": "deleted",
+    "instance_name": "web/1c2d3e4f-5a6b-7c8d-9e0f-1a2b3c4d5e6f",
+    "ip_addresses": ["10.244.1.10"],
+    "orphaned_at": "2022-08-02 10:00:00 UTC"
+  }
+]`
+
+const snapshots = `[
+  {
+    "job": "database",
+    "index": 0,
+    "uuid": "2b8f6c1d-9e3a-4f5b-8c7d-6e5f4a3b2c1d",
+    "snapshot_cid": "snap-0a1b2c3d",
+    "created_at": "2022-08-03 22:57:02 UTC",
+    "clean": true
+  },
+  {
+    "job": "database",
+    "index": 1,
+    "uuid": "7c6d5e4f-3a2b-4c1d-9e8f-0a1b2c3d4e5f",
+    "snapshot_cid": "snap-4e5f6a7b",
+    "created_at": "2022-08-03 22:57:05 UTC",
+    "clean": false
+  }
+]`
+
+const configs = `[
+  {
+    "id": "12",
+    "name": "default",
+    "type": "cloud",
+    "content": "azs:\n- name: z1\n",
+    "created_at": "2022-08-03 22:57:02 UTC",
+    "team": null,
+    "current": true
+  },
+  {
+    "id": "15",
+    "name": "dns",
+    "type": "runtime",
+    "content": "addons: []\n",
+    "created_at": "2022-08-04 10:12:45 UTC",
+    "team": "platform",
+    "current": true
+  }
+]`
+
+const config = `{
+  "id": "16",
+  "name": "syslog",
+  "type": "runtime",
+  "content": "releases: []\n",
+  "created_at": "2022-08-05 08:00:00 UTC",
+  "team": null,
+  "current": true
+}`
+
+const configDiff = `{
+  "diff": [
+    ["azs:", null],
+    ["- name: z1", null],
+    ["- name: z2", "added"],
+    ["- name: z3", "removed"]
+  ]
+}`
+
+const emptyConfigDiff = `{"diff": []}`
+
+const typedCloudConfig = `azs:
+- name: z1
+  cloud_properties:
+    zone: us-east-1a
+- name: z2
+  cloud_properties:
+    zone: us-east-1b
+
+vm_types:
+- name: default
+  cloud_properties:
+    instance_type: m5.large
+
+vm_extensions:
+- name: public-lb
+  cloud_properties:
+    elbs: [router]
+
+disk_types:
+- name: default
+  disk_size: 3072
+
+networks:
+- name: default
+  type: manual
+  subnets:
+  - az: z1
+    dns: [8.8.8.8]
+    range: 10.244.0.0/24
+    gateway: 10.244.0.1
+    reserved: [10.244.0.2 - 10.244.0.9]
+    static: [10.244.0.34, 10.244.0.40 - 10.244.0.49]
+  - azs: [z2]
+    range: 10.244.1.0/24
+    gateway: 10.244.1.1
+- name: public
+  type: vip
+
+compilation:
+  workers: 5
+  az: z1
+  reuse_compilation_vms: true
+  vm_type: default
+  network: default
+`
+
+const extendedCloudConfig = `azs:
+- name: z1
+  cpi: aws
+  cloud_properties:
+    zone: us-east-1a
+
+vm_types:
+- name: default
+  cloud_properties:
+    instance_type: m5.large
+
+disk_types:
+- name: default
+  disk_size: 3072
+
+networks:
+- name: default
+  type: manual
+  managed: true
+  subnets:
+  - name: default-z1
+    az: z1
+    range: 10.244.0.0/24
+    gateway: 10.244.0.1
+
+compilation:
+  workers: 5
+  az: z1
+  vm_type: default
+  network: default
+  orphan_workers: true
+  vm_extensions: [public-lb]
+  env:
+    bosh:
+      password: secret
+
+vm_extensions:
+- name: public-lb
+  cloud_properties:
+    elbs: [router]
+`
+
+const invalidCloudConfig = `azs:
+- name: z1
+- name: z1
+
+vm_types:
+- name: default
+
+networks:
+- name: default
+  subnets:
+  - az: z9
+    range: 10.244.0.0/24
+    gateway: 10.245.0.1
+    reserved: [10.244.0.2 - 10.244.0.9]
+    static: [10.244.0.5, 10.244.1.34]
+- name: other
+  subnets:
+  - az: z1
+    range: 10.244.0.128/25
+
+compilation:
+  workers: 5
+  az: z1
+  vm_type: large
+  network: missing
+`
+
+const typedRuntimeConfig = `releases:
+- name: os-conf
+  version: 22.1.2
+- name: syslog
+  version: 12.2.1
+addons:
+- name: os-configuration
+  jobs:
+  - name: login_banner
+    release: os-conf
+    properties:
+      login_banner:
+        text: authorized use only
+- name: syslog-forwarder
+  jobs:
+  - name: syslog_forwarder
+    release: syslog
+  include:
+    deployments: [cf]
+    stemcell:
+    - os: ubuntu-jammy
+  exclude:
+    instance_groups: [smoke-tests]
+- name: router-tuning
+  jobs:
+  - name: sysctl
+    release: os-conf
+  include:
+    jobs:
+    - name: gorouter
+      release: routing
+- name: platform-only
+  jobs:
+  - name: user_add
+    release: os-conf
+  include:
+    teams: [platform]
+    lifecycle: service
+tags:
+  env: prod
+`
+
+const addonDeploymentManifest = `name: cf
+releases:
+- name: routing
+  version: 0.250.0
+stemcells:
+- alias: default
+  os: ubuntu-jammy
+  version: latest
+- alias: windows
+  os: windows2019
+  version: latest
+instance_groups:
+- name: router
+  instances: 2
+  azs: [z1, z2]
+  stemcell: default
+  vm_type: default
+  networks:
+  - name: default
+  jobs:
+  - name: gorouter
+    release: routing
+- name: windows-cell
+  instances: 1
+  azs: [z1]
+  stemcell: windows
+  vm_type: default
+  networks:
+  - name: default
+  jobs:
+  - name: rep_windows
+    release: diego
+- name: smoke-tests
+  instances: 1
+  lifecycle: errand
+  azs: [z1]
+  stemcell: default
+  vm_type: default
+  networks:
+  - name: default
+  jobs:
+  - name: smoke_tests
+    release: cf-smoke-tests
+`
+
+const extendedRuntimeConfig = `releases:
+- name: os-conf
+  version: 22.1.2
+  stemcell:
+    os: ubuntu-jammy
+    version: "1.108"
+addons:
+- name: syslog-forwarder
+  jobs:
+  - name: syslog_forwarder
+    release: syslog
+    consumes:
+      syslog_storer: {from: syslog_storer}
+  properties:
+    syslog:
+      address: 10.244.0.10
+  include:
+    stemcell:
+    - os: ubuntu-jammy
+    deployments: [cf]
+  exclude:
+    jobs:
+    - name: smoke_tests
+      release: cf-smoke-tests
+      exclude_errands: true
+variables:
+- name: syslog_tls
+  type: certificate
+`
+
+const extendedDeploymentManifest = `name: cf
+releases:
+- name: routing
+  version: 0.250.0
+stemcells:
+- alias: default
+  name: bosh-aws-xen-hvm-ubuntu-jammy-go_agent
+  version: latest
+instance_groups:
+- name: router
+  instances: 2
+  azs: [z1, z2]
+  stemcell: default
+  vm_type: default
+  vm_extensions: [public-lb]
+  networks:
+  - name: default
+    static_ips: [10.244.0.34]
+  jobs:
+  - name: gorouter
+    release: routing
+    properties:
+      router:
+        port: 80
+  update:
+    max_in_flight: 1
+update:
+  canaries: 1
+  max_in_flight: 2
+  canary_watch_time: 1000-30000
+  update_watch_time: 1000-30000
+features:
+  use_dns_addresses: true
+`
+
+const namedStemcells = `[
+  {
+    "name": "bosh-aws-xen-hvm-ubuntu-jammy-go_agent",
+    "operating_system": "ubuntu-jammy",
+    "version": "1.108",
+    "cid": "ami-0a1b2c3d4e5f6a7b8"
+  }
+]`
+
+const namedStemcellManifest = `{
+  "manifest": "name: cf\nstemcells:\n- alias: default\n  name: bosh-aws-xen-hvm-ubuntu-jammy-go_agent\n  version: latest\ninstance_groups:\n- name: router\n  instances: 2\n  stemcell: default\n  jobs:\n  - name: gorouter\n    release: routing\n- name: smoke-tests\n  instances: 1\n  lifecycle: errand\n  stemcell: default\n  jobs:\n  - name: smoke_tests\n    release: cf-smoke-tests\n"
+}`
+
+const addonDeployments = `[
+  {
+    "name": "cf",
+    "cloud_config": "latest",
+    "releases": [{"name": "routing", "version": "0.250.0"}],
+    "stemcells": [{"name": "bosh-warden-boshlite-ubuntu-jammy-go_agent", "version": "1.92"}],
+    "teams": ["platform"]
+  }
+]`
+
+const addonManifest = `{
+  "manifest": "name: cf\nreleases:\n- name: routing\n  version: 0.250.0\nstemcells:\n- alias: default\n  os: ubuntu-jammy\n  version: latest\n- alias: windows\n  os: windows2019\n  version: latest\ninstance_groups:\n- name: router\n  instances: 2\n  azs: [z1, z2]\n  stemcell: default\n  vm_type: default\n  networks:\n  - name: default\n  jobs:\n  - name: gorouter\n    release: routing\n- name: windows-cell\n  instances: 1\n  azs: [z1]\n  stemcell: windows\n  vm_type: default\n  networks:\n  - name: default\n  jobs:\n  - name: rep_windows\n    release: diego\n- name: smoke-tests\n  instances: 1\n  lifecycle: errand\n  azs: [z1]\n  stemcell: default\n  vm_type: default\n  networks:\n  - name: default\n  jobs:\n  - name: smoke_tests\n    release: cf-smoke-tests\n"
+}`
+
+const runtimeConfigs = `[
+  {
+    "id": "21",
+    "name": "default",
+    "type": "runtime",
+    "content": "addons:\n- name: os-configuration\n  jobs:\n  - name: login_banner\n    release: os-conf\n- name: syslog-forwarder\n  jobs:\n  - name: syslog_forwarder\n    release: syslog\n  include:\n    stemcell:\n    - os: ubuntu-jammy\n  exclude:\n    lifecycle: errand\n",
+    "created_at": "2022-08-06 09:30:00 UTC",
+    "team": null,
+    "current": true
+  }
+]`
+
+const typedCPIConfig = `cpis:
+- name: vsphere-dc1
+  type: vsphere
+  properties:
+    host: vcenter-1.example.com
+- name: vsphere-dc2
+  type: vsphere
+  migrated_from:
+  - name: vsphere-legacy
+  properties:
+    host: vcenter-2.example.com
+`
+
+const extendedCPIConfig = `cpis:
+- name: openstack-region1
+  type: openstack
+  exec_path: /var/vcap/jobs/openstack_cpi/bin/cpi
+  migrated_from:
+  - name: openstack-legacy
+  properties:
+    auth_url: https://keystone.example.com:5000/v3
+`
+
+const cpiConfigs = `[
+  {
+    "id": "30",
+    "name": "default",
+    "type": "cpi",
+    "content": "cpis:\n- name: vsphere-dc1\n  type: vsphere\n  properties:\n    host: vcenter-1.example.com\n- name: vsphere-dc2\n  type: vsphere\n  migrated_from:\n  - name: vsphere-legacy\n  properties:\n    host: vcenter-2.example.com\n",
+    "created_at": "2022-08-07 11:00:00 UTC",
+    "team": null,
+    "current": true
+  }
+]`
+
+const cpiStemcells = `[
+  {
+    "name": "bosh-vsphere-esxi-ubuntu-jammy-go_agent",
+    "operating_system": "ubuntu-jammy",
+    "version": "1.92",
+    "cid": "sc-0a1b2c3d",
+    "cpi": "vsphere-dc1",
+    "deployments": [{"name": "cf"}]
+  },
+  {
+    "name": "bosh-vsphere-esxi-ubuntu-jammy-go_agent",
+    "operating_system": "ubuntu-jammy",
+    "version": "1.92",
+    "cid": "sc-4e5f6a7b",
+    "cpi": "vsphere-dc2",
+    "deployments": []
+  },
+  {
+    "name": "bosh-vsphere-esxi-windows2019-go_agent",
+    "operating_system": "windows2019",
+    "version": "2019.70",
+    "cid": "sc-8c9d0e1f",
+    "cpi": "vsphere-dc1",
+    "deployments": []
+  }
+]`
+
+const events = `[
+  {
+    "id": "1042",
+    "parent_id": "1041",
+    "timestamp": 1659657425,
+    "user": "admin",
+    "action": "update",
+    "object_type": "deployment",
+    "object_name": "cf",
+    "task": "312",
+    "deployment": "cf",
+    "context": {"before": {}, "after": {"releases": ["routing/0.250.0"]}}
+  },
+  {
+    "id": "1041",
+    "timestamp": 1659657300,
+    "user": "admin",
+    "action": "update",
+    "object_type": "deployment",
+    "object_name": "cf",
+    "task": "312",
+    "deployment": "cf",
+    "context": {}
+  }
+]`
+
+const event = `{
+  "id": "1042",
+  "parent_id": "1041",
+  "timestamp": 1659657425,
+  "user": "admin",
+  "action": "update",
+  "object_type": "deployment",
+  "object_name": "cf",
+  "task": "312",
+  "deployment": "cf",
+  "context": {"before": {}, "after": {"releases": ["routing/0.250.0"]}}
+}`
+
+const uploadTask = `{
+  "id": 7,
+  "state": "queued",
+  "description": "create release"
+}`
+
+const nginxReleaseMF = `name: nginx
+version: "1.21.6"
+commit_hash: 7c6d5e4f
+uncommitted_changes: false
+jobs:
+- name: nginx
+  version: 3f2a9c1e
+  fingerprint: 3f2a9c1e
+  sha1: 0a1b2c3d
+packages:
+- name: nginx
+  version: fp-nginx
+  fingerprint: fp-nginx
+  sha1: 4e5f6a7b
+  dependencies: [pcre]
+- name: pcre
+  version: fp-pcre
+  fingerprint: fp-pcre
+  sha1: 8c9d0e1f
+  dependencies: []
+`
+
+const stemcellMF = `name: bosh-warden-boshlite-ubuntu-trusty-go_agent
+version: "3126"
+operating_system: ubuntu-trusty
+sha1: 4d6823188f510215355643ad766300e076ec2e5a
+cloud_properties:
+  infrastructure: warden
+`
+
+const packageMatches = `["fp-nginx"]`
+
+const releaseVersions = `[
+  {
+    "name": "nginx",
+    "release_versions": [
+      {"version": "1.21.5", "commit_hash": "2b8f6c1d", "uncommitted_changes": false, "currently_deployed": false},
+      {"version": "1.21.6", "commit_hash": "7c6d5e4f", "uncommitted_changes": false, "currently_deployed": true}
+    ]
+  }
+]`
+
+const instanceStates = `{"vm_cid":"ec974048-3352-4ba4-669d-beab87b16bcb","disk_cid":null,"disk_cids":[],"ips":["10.244.0.142"],"dns":[],"agent_id":"c5e7c705-459e-41c0-b640-db32d8dc6e71","job_name":"doppler_z1","index":0,"job_state":"running","state":"started","vm_type":"default","vitals":null,"processes":[],"resurrection_paused":true,"az":"z1","id":"4a9278c8","bootstrap":true,"ignore":true}
+{"vm_cid":null,"disk_cid":null,"disk_cids":[],"ips":[],"dns":[],"agent_id":null,"job_name":"doppler_z1","index":1,"job_state":null,"state":"detached","vm_type":"default","vitals":null,"processes":[],"resurrection_paused":false,"az":"z2","id":"9f0c2d4b","bootstrap":false,"ignore":true}
+`
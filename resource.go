@@ -0,0 +1,179 @@
+package gogobosh
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"golang.org/x/net/context"
+)
+
+// DownloadOptions configures how a resource is downloaded from the director blobstore
+type DownloadOptions struct {
+	// Offset resumes an interrupted download by only requesting the bytes from Offset onwards
+	Offset int64
+	// Digest is the expected digest of the whole resource, either a bare SHA1
+	// or a multi-digest like "sha256:abc;sha1:def". The strongest algorithm is verified.
+	Digest string
+	// Progress is called after every write with the number of bytes downloaded
+	// so far, including Offset, and the total size or -1 when it is unknown
+	Progress func(written, total int64)
+}
+
+// DownloadResource streams the resource with the given blobstore ID into w
+func (c *Client) DownloadResource(ctx context.Context, id string, w io.Writer) error {
+	return c.DownloadResourceWithOptions(ctx, id, w, DownloadOptions{})
+}
+
+// DownloadResourceWithOptions streams the resource with the given blobstore ID
+// into w. A digest can only be verified when downloading from the start, use
+// DownloadResourceToFile to verify resumed downloads.
+func (c *Client) DownloadResourceWithOptions(ctx context.Context, id string, w io.Writer, opts DownloadOptions) error {
+	if opts.Digest == "" {
+		return c.downloadResource(ctx, id, w, opts)
+	}
+	if opts.Offset > 0 {
+		return fmt.Errorf("error downloading resource %s: cannot verify the digest of a partial download", id)
+	}
+
+	expected, h, err := resourceDigest(opts.Digest)
+	if err != nil {
+		return fmt.Errorf("error downloading resource %s: %w", id, err)
+	}
+	err = c.downloadResource(ctx, id, io.MultiWriter(w, h), opts)
+	if err != nil {
+		return err
+	}
+	if err = expected.verify(h); err != nil {
+		return fmt.Errorf("error verifying resource %s: %w", id, err)
+	}
+	return nil
+}
+
+// DownloadResourceToFile downloads the resource with the given blobstore ID
+// into path. An existing file is treated as a previous partial download and
+// only the remaining bytes are requested. The file is removed when it does
+// not match the digest, so that the next attempt starts over. It is kept
+// when it is longer than the resource, the caller decides whether to remove it.
+func (c *Client) DownloadResourceToFile(ctx context.Context, id, path string, opts DownloadOptions) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if opts.Digest == "" {
+		opts.Offset, err = f.Seek(0, io.SeekEnd)
+		if err != nil {
+			return fmt.Errorf("error reading partial download %s: %w", path, err)
+		}
+		return c.downloadResource(ctx, id, f, opts)
+	}
+
+	expected, h, err := resourceDigest(opts.Digest)
+	if err != nil {
+		return fmt.Errorf("error downloading resource %s: %w", id, err)
+	}
+	// Seed the digest with what was downloaded before, leaving the file at its end
+	opts.Offset, err = io.Copy(h, f)
+	if err != nil {
+		return fmt.Errorf("error reading partial download %s: %w", path, err)
+	}
+	err = c.downloadResource(ctx, id, io.MultiWriter(f, h), opts)
+	if err != nil {
+		return err
+	}
+	if err = expected.verify(h); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return fmt.Errorf("error verifying resource %s: %w", id, err)
+	}
+	return nil
+}
+
+func resourceDigest(s string) (digest, hash.Hash, error) {
+	digests, err := parseDigests(s)
+	if err != nil {
+		return digest{}, nil, err
+	}
+	expected := strongestDigest(digests)
+	h, err := newDigestHash(expected.algorithm)
+	return expected, h, err
+}
+
+// downloadResource streams the resource into w starting at opts.Offset
+func (c *Client) downloadResource(ctx context.Context, id string, w io.Writer, opts DownloadOptions) error {
+	r := c.NewRequest("GET", "/resources/"+id)
+	r.ctx = ctx
+	if opts.Offset > 0 {
+		r.header["Range"] = "bytes=" + strconv.FormatInt(opts.Offset, 10) + "-"
+	}
+	resp, err := c.DoRequest(r)
+	var statusErr *statusError
+	if opts.Offset > 0 && errors.As(err, &statusErr) && statusErr.statusCode == http.StatusRequestedRangeNotSatisfiable {
+		// Nothing is left after the offset, the download is already complete
+		if opts.Progress != nil {
+			opts.Progress(opts.Offset, opts.Offset)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error downloading resource %s: %w", id, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	total := resp.ContentLength
+	if opts.Offset > 0 {
+		if resp.StatusCode == http.StatusPartialContent {
+			if total >= 0 {
+				total += opts.Offset
+			}
+		} else if total >= 0 && total == opts.Offset {
+			// The director ignored the range and there is nothing new
+			if opts.Progress != nil {
+				opts.Progress(opts.Offset, total)
+			}
+			return nil
+		} else if total >= 0 && total < opts.Offset {
+			return fmt.Errorf("error resuming resource %s: already have %d bytes but it only has %d", id, opts.Offset, total)
+		} else {
+			// The director ignored the range, skip what we already have
+			skipped, err := io.CopyN(io.Discard, resp.Body, opts.Offset)
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("error resuming resource %s: already have %d bytes but it only has %d", id, opts.Offset, skipped)
+			}
+			if err != nil {
+				return fmt.Errorf("error skipping the first %d bytes of resource %s: %w", opts.Offset, id, err)
+			}
+		}
+	}
+
+	if opts.Progress != nil {
+		w = &progressWriter{w: w, written: opts.Offset, total: total, progress: opts.Progress}
+		opts.Progress(opts.Offset, total)
+	}
+	_, err = io.Copy(w, resp.Body)
+	if err != nil {
+		return fmt.Errorf("error downloading resource %s: %w", id, err)
+	}
+	return nil
+}
+
+// progressWriter reports the number of bytes written through it
+type progressWriter struct {
+	w        io.Writer
+	written  int64
+	total    int64
+	progress func(written, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	p.progress(p.written, p.total)
+	return n, err
+}
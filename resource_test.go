@@ -0,0 +1,166 @@
+package gogobosh_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Resource", func() {
+	Describe("Test download resource", func() {
+		var (
+			client  *Client
+			content string
+			digest  string
+		)
+
+		BeforeEach(func() {
+			content = strings.Repeat("compiled package bits\n", 1000)
+			sha1Sum := sha1.Sum([]byte(content))
+			sha256Sum := sha256.Sum256([]byte(content))
+			digest = "sha256:" + hex.EncodeToString(sha256Sum[:]) + ";sha1:" + hex.EncodeToString(sha1Sum[:])
+
+			setupMockRoute(MockRoute{"GET", "/resources/e8b2b4f1-1b1a-4b9e-9d2c-6b1e7e0c3a11", content, ""}, "basic")
+			mux.HandleFunc("/resources/ranged", func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "ranged", time.Now(), strings.NewReader(content))
+			})
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("can stream a resource", func() {
+			var out bytes.Buffer
+			err := client.DownloadResource(context.Background(), "e8b2b4f1-1b1a-4b9e-9d2c-6b1e7e0c3a11", &out)
+			Expect(err).Should(BeNil())
+			Expect(out.String()).Should(Equal(content))
+		})
+
+		It("verifies the digest and reports progress", func() {
+			var out bytes.Buffer
+			var written, total int64
+			err := client.DownloadResourceWithOptions(context.Background(), "ranged", &out, DownloadOptions{
+				Digest: digest,
+				Progress: func(w, t int64) {
+					written, total = w, t
+				},
+			})
+			Expect(err).Should(BeNil())
+			Expect(written).Should(Equal(int64(len(content))))
+			Expect(total).Should(Equal(int64(len(content))))
+		})
+
+		It("fails on a digest mismatch", func() {
+			var out bytes.Buffer
+			err := client.DownloadResourceWithOptions(context.Background(), "e8b2b4f1-1b1a-4b9e-9d2c-6b1e7e0c3a11", &out, DownloadOptions{
+				Digest: "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			})
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("expected sha1 digest"))
+		})
+
+		It("resumes a partial download with a range request", func() {
+			var out bytes.Buffer
+			err := client.DownloadResourceWithOptions(context.Background(), "ranged", &out, DownloadOptions{
+				Offset: 100,
+			})
+			Expect(err).Should(BeNil())
+			Expect(out.String()).Should(Equal(content[100:]))
+		})
+
+		It("resumes and verifies a partial download in a file", func() {
+			dir, err := os.MkdirTemp("", "gogobosh-resource")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			for _, id := range []string{"ranged", "e8b2b4f1-1b1a-4b9e-9d2c-6b1e7e0c3a11"} {
+				path := filepath.Join(dir, id)
+				Expect(os.WriteFile(path, []byte(content[:1234]), 0644)).To(Succeed())
+
+				err = client.DownloadResourceToFile(context.Background(), id, path, DownloadOptions{Digest: digest})
+				Expect(err).Should(BeNil())
+				b, err := os.ReadFile(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).Should(Equal(content))
+			}
+		})
+
+		It("treats resuming a complete download as done", func() {
+			dir, err := os.MkdirTemp("", "gogobosh-resource")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			for _, id := range []string{"ranged", "e8b2b4f1-1b1a-4b9e-9d2c-6b1e7e0c3a11"} {
+				path := filepath.Join(dir, id)
+				Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+
+				var written, total int64
+				err = client.DownloadResourceToFile(context.Background(), id, path, DownloadOptions{
+					Digest: digest,
+					Progress: func(w, t int64) {
+						written, total = w, t
+					},
+				})
+				Expect(err).Should(BeNil())
+				Expect(written).Should(Equal(int64(len(content))))
+				if id == "ranged" {
+					Expect(total).Should(Equal(int64(len(content))))
+				}
+				b, err := os.ReadFile(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).Should(Equal(content))
+			}
+		})
+
+		It("fails to resume a download longer than the resource", func() {
+			dir, err := os.MkdirTemp("", "gogobosh-resource")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			mux.HandleFunc("/resources/sized", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+				_, _ = io.WriteString(w, content)
+			})
+			for _, id := range []string{"sized", "e8b2b4f1-1b1a-4b9e-9d2c-6b1e7e0c3a11"} {
+				path := filepath.Join(dir, id)
+				Expect(os.WriteFile(path, []byte(content+"stale bits\n"), 0644)).To(Succeed())
+				err = client.DownloadResourceToFile(context.Background(), id, path, DownloadOptions{})
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).Should(ContainSubstring("only has %d", len(content)))
+			}
+		})
+
+		It("removes a downloaded file that does not match the digest", func() {
+			dir, err := os.MkdirTemp("", "gogobosh-resource")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			path := filepath.Join(dir, "corrupt")
+			Expect(os.WriteFile(path, []byte("corrupt"), 0644)).To(Succeed())
+			err = client.DownloadResourceToFile(context.Background(), "ranged", path, DownloadOptions{Digest: digest})
+			Expect(err).Should(HaveOccurred())
+			Expect(path).ShouldNot(BeAnExistingFile())
+		})
+	})
+})
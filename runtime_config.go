@@ -0,0 +1,264 @@
+package gogobosh
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuntimeConfig is the typed form of a runtime config
+type RuntimeConfig struct {
+	Releases []RuntimeConfigRelease `yaml:"releases,omitempty"`
+	Addons   []Addon                `yaml:"addons,omitempty"`
+	Tags     map[string]string      `yaml:"tags,omitempty"`
+
+	// Extra keeps the keys that are not modeled, so that a parsed runtime config
+	// can be updated without losing them. The nested types keep theirs the same way.
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// RuntimeConfigRelease is a release the addons of a runtime config come from
+type RuntimeConfigRelease struct {
+	Name    string                 `yaml:"name"`
+	Version string                 `yaml:"version"`
+	URL     string                 `yaml:"url,omitempty"`
+	SHA1    string                 `yaml:"sha1,omitempty"`
+	Extra   map[string]interface{} `yaml:",inline"`
+}
+
+// Addon is a set of jobs colocated on the instance groups matching its
+// include rules and none of its exclude rules
+type Addon struct {
+	Name    string                 `yaml:"name"`
+	Jobs    []AddonJob             `yaml:"jobs,omitempty"`
+	Include *AddonPlacement        `yaml:"include,omitempty"`
+	Exclude *AddonPlacement        `yaml:"exclude,omitempty"`
+	Extra   map[string]interface{} `yaml:",inline"`
+}
+
+// AddonJob is a release job added by an addon
+type AddonJob struct {
+	Name       string                 `yaml:"name"`
+	Release    string                 `yaml:"release"`
+	Properties map[string]interface{} `yaml:"properties,omitempty"`
+	Extra      map[string]interface{} `yaml:",inline"`
+}
+
+// AddonPlacement is an include or exclude rule of an addon. An instance group
+// matches when it matches every criterion that is set, and it matches a
+// criterion when it matches any of its values.
+type AddonPlacement struct {
+	Deployments    []string                 `yaml:"deployments,omitempty"`
+	Jobs           []AddonPlacementJob      `yaml:"jobs,omitempty"`
+	InstanceGroups []string                 `yaml:"instance_groups,omitempty"`
+	Stemcell       []AddonPlacementStemcell `yaml:"stemcell,omitempty"`
+	Networks       []string                 `yaml:"networks,omitempty"`
+	Teams          []string                 `yaml:"teams,omitempty"`
+	AZs            []string                 `yaml:"azs,omitempty"`
+	Lifecycle      string                   `yaml:"lifecycle,omitempty"`
+	Extra          map[string]interface{}   `yaml:",inline"`
+}
+
+// AddonPlacementJob matches instance groups running the job
+type AddonPlacementJob struct {
+	Name    string                 `yaml:"name"`
+	Release string                 `yaml:"release"`
+	Extra   map[string]interface{} `yaml:",inline"`
+}
+
+// AddonPlacementStemcell matches instance groups using a stemcell of the operating system
+type AddonPlacementStemcell struct {
+	OS    string                 `yaml:"os"`
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// AddonApplication lists the instance groups of a deployment an addon is applied to
+type AddonApplication struct {
+	// Config is the name of the runtime config the addon comes from, if known
+	Config         string
+	Addon          string
+	InstanceGroups []string
+}
+
+// ParseRuntimeConfig parses the YAML content of a runtime config
+func ParseRuntimeConfig(content string) (RuntimeConfig, error) {
+	var rc RuntimeConfig
+	err := yaml.Unmarshal([]byte(content), &rc)
+	if err != nil {
+		return RuntimeConfig{}, fmt.Errorf("error parsing runtime config: %w", err)
+	}
+	return rc, nil
+}
+
+// Marshal renders the runtime config as YAML
+func (rc RuntimeConfig) Marshal() (string, error) {
+	b, err := yaml.Marshal(&rc)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling runtime config: %w", err)
+	}
+	return string(b), nil
+}
+
+// Evaluate reports, for every addon, the instance groups of the deployment it
+// will be applied to. Addons that apply nowhere are reported with no instance
+// groups. Stemcells the manifest gives by name only match stemcell rules once
+// resolved with ResolveStemcellOS.
+func (rc RuntimeConfig) Evaluate(deployment Deployment, manifest DeploymentManifest) []AddonApplication {
+	var applications []AddonApplication
+	for _, addon := range rc.Addons {
+		application := AddonApplication{Addon: addon.Name}
+		for _, ig := range manifest.InstanceGroups {
+			if addon.AppliesTo(deployment, manifest, ig) {
+				application.InstanceGroups = append(application.InstanceGroups, ig.Name)
+			}
+		}
+		applications = append(applications, application)
+	}
+	return applications
+}
+
+// AppliesTo reports whether the addon is applied to the instance group of the deployment
+func (a Addon) AppliesTo(deployment Deployment, manifest DeploymentManifest, ig InstanceGroup) bool {
+	if a.Include != nil && !a.Include.isEmpty() && !a.Include.matches(deployment, manifest, ig) {
+		return false
+	}
+	if a.Exclude != nil && !a.Exclude.isEmpty() && a.Exclude.matches(deployment, manifest, ig) {
+		return false
+	}
+	return true
+}
+
+func (p AddonPlacement) isEmpty() bool {
+	return len(p.Deployments) == 0 && len(p.Jobs) == 0 && len(p.InstanceGroups) == 0 &&
+		len(p.Stemcell) == 0 && len(p.Networks) == 0 && len(p.Teams) == 0 &&
+		len(p.AZs) == 0 && p.Lifecycle == ""
+}
+
+func (p AddonPlacement) matches(deployment Deployment, manifest DeploymentManifest, ig InstanceGroup) bool {
+	if len(p.Deployments) > 0 && !containsString(p.Deployments, deployment.Name) {
+		return false
+	}
+	if len(p.Teams) > 0 && !containsAny(p.Teams, deployment.Teams) {
+		return false
+	}
+	if len(p.InstanceGroups) > 0 && !containsString(p.InstanceGroups, ig.Name) {
+		return false
+	}
+	if len(p.Jobs) > 0 && !p.matchesJobs(ig) {
+		return false
+	}
+	if len(p.Stemcell) > 0 && !p.matchesStemcell(manifest.stemcellOS(ig.Stemcell)) {
+		return false
+	}
+	if len(p.Networks) > 0 {
+		var networks []string
+		for _, network := range ig.Networks {
+			networks = append(networks, network.Name)
+		}
+		if !containsAny(p.Networks, networks) {
+			return false
+		}
+	}
+	if len(p.AZs) > 0 && !containsAny(p.AZs, ig.AZs) {
+		return false
+	}
+	if p.Lifecycle != "" {
+		lifecycle := ig.Lifecycle
+		if lifecycle == "" {
+			lifecycle = "service"
+		}
+		if p.Lifecycle != lifecycle {
+			return false
+		}
+	}
+	return true
+}
+
+func (p AddonPlacement) matchesJobs(ig InstanceGroup) bool {
+	for _, want := range p.Jobs {
+		for _, job := range ig.Jobs {
+			if job.Name == want.Name && job.Release == want.Release {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p AddonPlacement) matchesStemcell(os string) bool {
+	for _, stemcell := range p.Stemcell {
+		if stemcell.OS == os {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(list, values []string) bool {
+	for _, value := range values {
+		if containsString(list, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAddonApplications evaluates every current runtime config against the
+// deployment and reports which addons are applied to which instance groups
+func (c *Client) GetAddonApplications(name string) ([]AddonApplication, error) {
+	deployments, err := c.GetDeployments()
+	if err != nil {
+		return []AddonApplication{}, err
+	}
+	var deployment Deployment
+	for _, d := range deployments {
+		if d.Name == name {
+			deployment = d
+		}
+	}
+	if deployment.Name == "" {
+		return []AddonApplication{}, fmt.Errorf("deployment %s not found", name)
+	}
+
+	m, err := c.GetDeployment(name)
+	if err != nil {
+		return []AddonApplication{}, err
+	}
+	manifest, err := m.Parse()
+	if err != nil {
+		return []AddonApplication{}, err
+	}
+	if manifest.needsStemcellOS() {
+		stemcells, err := c.GetStemcells()
+		if err != nil {
+			return []AddonApplication{}, err
+		}
+		manifest = manifest.ResolveStemcellOS(stemcells)
+	}
+
+	cfgs, err := c.GetConfigs(ConfigsFilter{Type: ConfigTypeRuntime, Latest: true})
+	if err != nil {
+		return []AddonApplication{}, err
+	}
+	var applications []AddonApplication
+	for _, cfg := range cfgs {
+		rc, err := ParseRuntimeConfig(cfg.Content)
+		if err != nil {
+			return []AddonApplication{}, fmt.Errorf("error in runtime config %s: %w", cfg.Name, err)
+		}
+		for _, application := range rc.Evaluate(deployment, manifest) {
+			application.Config = cfg.Name
+			applications = append(applications, application)
+		}
+	}
+	return applications, nil
+}
@@ -0,0 +1,191 @@
+package gogobosh_test
+
+import (
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RuntimeConfig", func() {
+	Describe("Test typed runtime config", func() {
+		It("can parse a runtime config", func() {
+			rc, err := ParseRuntimeConfig(typedRuntimeConfig)
+			Expect(err).Should(BeNil())
+			Expect(rc.Releases).Should(HaveLen(2))
+			Expect(rc.Releases[0].Name).Should(Equal("os-conf"))
+			Expect(rc.Addons).Should(HaveLen(4))
+			Expect(rc.Addons[0].Include).Should(BeNil())
+			Expect(rc.Addons[0].Jobs[0].Properties).Should(HaveKey("login_banner"))
+			Expect(rc.Addons[1].Include.Deployments).Should(Equal([]string{"cf"}))
+			Expect(rc.Addons[1].Include.Stemcell[0].OS).Should(Equal("ubuntu-jammy"))
+			Expect(rc.Addons[1].Exclude.InstanceGroups).Should(Equal([]string{"smoke-tests"}))
+			Expect(rc.Addons[2].Include.Jobs[0]).Should(Equal(AddonPlacementJob{Name: "gorouter", Release: "routing"}))
+			Expect(rc.Tags).Should(HaveKeyWithValue("env", "prod"))
+		})
+
+		It("round trips through YAML", func() {
+			rc, err := ParseRuntimeConfig(typedRuntimeConfig)
+			Expect(err).Should(BeNil())
+			content, err := rc.Marshal()
+			Expect(err).Should(BeNil())
+			Expect(content).Should(MatchYAML(typedRuntimeConfig))
+		})
+
+		It("keeps the runtime config keys it does not model", func() {
+			rc, err := ParseRuntimeConfig(extendedRuntimeConfig)
+			Expect(err).Should(BeNil())
+			Expect(rc.Extra).Should(HaveKey("variables"))
+			Expect(rc.Releases[0].Extra).Should(HaveKey("stemcell"))
+			Expect(rc.Addons[0].Extra).Should(HaveKey("properties"))
+			Expect(rc.Addons[0].Jobs[0].Extra).Should(HaveKey("consumes"))
+			Expect(rc.Addons[0].Exclude.Jobs[0].Extra).Should(HaveKeyWithValue("exclude_errands", true))
+
+			content, err := rc.Marshal()
+			Expect(err).Should(BeNil())
+			Expect(content).Should(MatchYAML(extendedRuntimeConfig))
+		})
+
+		It("can parse a deployment manifest", func() {
+			m, err := ParseManifest(addonDeploymentManifest)
+			Expect(err).Should(BeNil())
+			Expect(m.Name).Should(Equal("cf"))
+			Expect(m.Stemcells[1].OS).Should(Equal("windows2019"))
+			Expect(m.InstanceGroups).Should(HaveLen(3))
+			Expect(m.InstanceGroups[0].Networks[0].Name).Should(Equal("default"))
+			Expect(m.InstanceGroups[2].Lifecycle).Should(Equal("errand"))
+		})
+
+		It("keeps the manifest keys it does not model", func() {
+			m, err := ParseManifest(extendedDeploymentManifest)
+			Expect(err).Should(BeNil())
+			Expect(m.Extra).Should(HaveKey("update"))
+			Expect(m.Extra).Should(HaveKey("features"))
+			Expect(m.InstanceGroups[0].Extra).Should(HaveKey("vm_extensions"))
+			Expect(m.InstanceGroups[0].Jobs[0].Extra).Should(HaveKey("properties"))
+
+			content, err := m.Marshal()
+			Expect(err).Should(BeNil())
+			Expect(content).Should(MatchYAML(extendedDeploymentManifest))
+		})
+
+		It("resolves the operating system of stemcells given by name", func() {
+			m, err := ParseManifest(extendedDeploymentManifest)
+			Expect(err).Should(BeNil())
+			resolved := m.ResolveStemcellOS([]Stemcell{
+				{Name: "bosh-warden-boshlite-ubuntu-trusty-go_agent", OperatingSystem: "ubuntu-trusty"},
+				{Name: "bosh-aws-xen-hvm-ubuntu-jammy-go_agent", OperatingSystem: "ubuntu-jammy"},
+			})
+			Expect(resolved.Stemcells[0].OS).Should(Equal("ubuntu-jammy"))
+			Expect(m.Stemcells[0].OS).Should(BeEmpty())
+
+			rc, err := ParseRuntimeConfig(extendedRuntimeConfig)
+			Expect(err).Should(BeNil())
+			Expect(rc.Evaluate(Deployment{Name: "cf"}, m)[0].InstanceGroups).Should(BeEmpty())
+			Expect(rc.Evaluate(Deployment{Name: "cf"}, resolved)[0].InstanceGroups).Should(Equal([]string{"router"}))
+		})
+
+		It("reports where addons are applied", func() {
+			rc, err := ParseRuntimeConfig(typedRuntimeConfig)
+			Expect(err).Should(BeNil())
+			m, err := ParseManifest(addonDeploymentManifest)
+			Expect(err).Should(BeNil())
+
+			applications := rc.Evaluate(Deployment{Name: "cf", Teams: []string{"platform"}}, m)
+			Expect(applications).Should(Equal([]AddonApplication{
+				{Addon: "os-configuration", InstanceGroups: []string{"router", "windows-cell", "smoke-tests"}},
+				{Addon: "syslog-forwarder", InstanceGroups: []string{"router"}},
+				{Addon: "router-tuning", InstanceGroups: []string{"router"}},
+				{Addon: "platform-only", InstanceGroups: []string{"router", "windows-cell"}},
+			}))
+		})
+
+		It("does not apply addons to other deployments or teams", func() {
+			rc, err := ParseRuntimeConfig(typedRuntimeConfig)
+			Expect(err).Should(BeNil())
+			m, err := ParseManifest(addonDeploymentManifest)
+			Expect(err).Should(BeNil())
+
+			applications := rc.Evaluate(Deployment{Name: "other"}, m)
+			Expect(applications[1].InstanceGroups).Should(BeEmpty())
+			Expect(applications[3].InstanceGroups).Should(BeEmpty())
+		})
+	})
+
+	Describe("Test addon applications", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"GET", "/deployments", addonDeployments, ""},
+				{"GET", "/deployments/cf", addonManifest, ""},
+				{"GET", "/configs", runtimeConfigs, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("evaluates the current runtime configs against a deployment", func() {
+			applications, err := client.GetAddonApplications("cf")
+			Expect(err).Should(BeNil())
+			Expect(receivedRequests["GET /configs"].Query).Should(HaveKeyWithValue("type", []string{"runtime"}))
+			Expect(applications).Should(Equal([]AddonApplication{
+				{Config: "default", Addon: "os-configuration", InstanceGroups: []string{"router", "windows-cell", "smoke-tests"}},
+				{Config: "default", Addon: "syslog-forwarder", InstanceGroups: []string{"router"}},
+			}))
+		})
+
+		It("fails for an unknown deployment", func() {
+			_, err := client.GetAddonApplications("missing")
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("does not look up stemcells when the manifest gives their os", func() {
+			_, err := client.GetAddonApplications("cf")
+			Expect(err).Should(BeNil())
+			Expect(receivedRequests).ShouldNot(HaveKey("GET /stemcells"))
+		})
+	})
+
+	Describe("Test addon applications with stemcells given by name", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"GET", "/deployments", addonDeployments, ""},
+				{"GET", "/deployments/cf", namedStemcellManifest, ""},
+				{"GET", "/configs", runtimeConfigs, ""},
+				{"GET", "/stemcells", namedStemcells, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("matches stemcell rules against the director stemcells", func() {
+			applications, err := client.GetAddonApplications("cf")
+			Expect(err).Should(BeNil())
+			Expect(receivedRequests).Should(HaveKey("GET /stemcells"))
+			Expect(applications).Should(Equal([]AddonApplication{
+				{Config: "default", Addon: "os-configuration", InstanceGroups: []string{"router", "smoke-tests"}},
+				{Config: "default", Addon: "syslog-forwarder", InstanceGroups: []string{"router"}},
+			}))
+		})
+	})
+})
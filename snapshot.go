@@ -0,0 +1,98 @@
+package gogobosh
+
+import (
+	"fmt"
+)
+
+// GetSnapshots returns the snapshots of every instance of the deployment
+func (c *Client) GetSnapshots(deployment string) ([]Snapshot, error) {
+	r := c.NewRequest("GET", "/deployments/"+deployment+"/snapshots")
+	var snapshots []Snapshot
+	err := c.DoRequestAndUnmarshal(r, &snapshots)
+	if err != nil {
+		return []Snapshot{}, fmt.Errorf("error requesting deployment %s snapshots: %w", deployment, err)
+	}
+	return snapshots, nil
+}
+
+// GetInstanceSnapshots returns the snapshots of a single instance
+func (c *Client) GetInstanceSnapshots(deployment, instanceGroup, instanceID string) ([]Snapshot, error) {
+	r := c.NewRequest("GET", fmt.Sprintf("/deployments/%s/jobs/%s/%s/snapshots", deployment, instanceGroup, instanceID))
+	var snapshots []Snapshot
+	err := c.DoRequestAndUnmarshal(r, &snapshots)
+	if err != nil {
+		return []Snapshot{}, fmt.Errorf("error requesting instance %s/%s snapshots: %w", instanceGroup, instanceID, err)
+	}
+	return snapshots, nil
+}
+
+// TakeSnapshot snapshots the persistent disks of every instance of the deployment
+func (c *Client) TakeSnapshot(deployment string) (Task, error) {
+	r := c.NewRequest("POST", "/deployments/"+deployment+"/snapshots")
+	r.header["Content-Type"] = "application/json"
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error taking deployment %s snapshot: %w", deployment, err)
+	}
+	return task, nil
+}
+
+// TakeInstanceSnapshot snapshots the persistent disk of a single instance
+func (c *Client) TakeInstanceSnapshot(deployment, instanceGroup, instanceID string) (Task, error) {
+	r := c.NewRequest("POST", fmt.Sprintf("/deployments/%s/jobs/%s/%s/snapshots", deployment, instanceGroup, instanceID))
+	r.header["Content-Type"] = "application/json"
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error taking instance %s/%s snapshot: %w", instanceGroup, instanceID, err)
+	}
+	return task, nil
+}
+
+// TakeInstanceGroupSnapshots snapshots every instance of the instance group
+// and returns a task per instance
+func (c *Client) TakeInstanceGroupSnapshots(deployment, instanceGroup string) ([]Task, error) {
+	vms, err := c.GetDeploymentVMsWithOptions(deployment, VMsOptions{})
+	if err != nil {
+		return []Task{}, err
+	}
+
+	var tasks []Task
+	for _, vm := range vms {
+		if vm.JobName != instanceGroup {
+			continue
+		}
+		task, err := c.TakeInstanceSnapshot(deployment, instanceGroup, vm.ID)
+		if err != nil {
+			return tasks, err
+		}
+		tasks = append(tasks, task)
+	}
+	if len(tasks) == 0 {
+		return []Task{}, fmt.Errorf("no instances of %s found in deployment %s", instanceGroup, deployment)
+	}
+	return tasks, nil
+}
+
+// DeleteSnapshots deletes all the snapshots of the deployment
+func (c *Client) DeleteSnapshots(deployment string) (Task, error) {
+	r := c.NewRequest("DELETE", "/deployments/"+deployment+"/snapshots")
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error deleting deployment %s snapshots: %w", deployment, err)
+	}
+	return task, nil
+}
+
+// DeleteSnapshot deletes a single snapshot of the deployment
+func (c *Client) DeleteSnapshot(deployment, snapshotCID string) (Task, error) {
+	r := c.NewRequest("DELETE", "/deployments/"+deployment+"/snapshots/"+snapshotCID)
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error deleting snapshot %s: %w", snapshotCID, err)
+	}
+	return task, nil
+}
@@ -0,0 +1,81 @@
+package gogobosh_test
+
+import (
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshot", func() {
+	Describe("Test snapshots", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"GET", "/deployments/cf-warden/snapshots", snapshots, ""},
+				{"GET", "/deployments/cf-warden/jobs/database/2b8f6c1d-9e3a-4f5b-8c7d-6e5f4a3b2c1d/snapshots", snapshots, ""},
+				{"POST", "/deployments/cf-warden/snapshots", "", "/tasks/2"},
+				{"POST", "/deployments/cf-warden/jobs/doppler_z1/:id/snapshots", "", "/tasks/2"},
+				{"DELETE", "/deployments/cf-warden/snapshots", "", "/tasks/2"},
+				{"DELETE", "/deployments/cf-warden/snapshots/snap-0a1b2c3d", "", "/tasks/2"},
+				{"GET", "/deployments/cf-warden/vms", basicVMs, ""},
+				{"GET", "/tasks/2", task, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("can get deployment snapshots", func() {
+			snapshots, err := client.GetSnapshots("cf-warden")
+			Expect(err).Should(BeNil())
+			Expect(snapshots).Should(HaveLen(2))
+			Expect(snapshots[0].Job).Should(Equal("database"))
+			Expect(snapshots[0].Index).Should(Equal(0))
+			Expect(snapshots[0].ID).Should(Equal("2b8f6c1d-9e3a-4f5b-8c7d-6e5f4a3b2c1d"))
+			Expect(snapshots[0].SnapshotCID).Should(Equal("snap-0a1b2c3d"))
+			Expect(snapshots[0].CreatedAt).Should(Equal("2022-08-03 22:57:02 UTC"))
+			Expect(snapshots[0].Clean).Should(BeTrue())
+			Expect(snapshots[1].Clean).Should(BeFalse())
+		})
+
+		It("can get instance snapshots", func() {
+			snapshots, err := client.GetInstanceSnapshots("cf-warden", "database", "2b8f6c1d-9e3a-4f5b-8c7d-6e5f4a3b2c1d")
+			Expect(err).Should(BeNil())
+			Expect(snapshots).Should(HaveLen(2))
+		})
+
+		It("can take and delete snapshots", func() {
+			task, err := client.TakeSnapshot("cf-warden")
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(2))
+
+			task, err = client.DeleteSnapshot("cf-warden", "snap-0a1b2c3d")
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(2))
+
+			task, err = client.DeleteSnapshots("cf-warden")
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(2))
+		})
+
+		It("can snapshot every instance of a group", func() {
+			tasks, err := client.TakeInstanceGroupSnapshots("cf-warden", "doppler_z1")
+			Expect(err).Should(BeNil())
+			Expect(tasks).Should(HaveLen(2))
+			Expect(receivedRequests).Should(HaveKey("POST /deployments/cf-warden/jobs/doppler_z1/4a9278c8-e93a-4d6a-b22c-13560208da9e/snapshots"))
+			Expect(receivedRequests).Should(HaveKey("POST /deployments/cf-warden/jobs/doppler_z1/9f0c2d4b-7a13-4c8e-b5d6-1e2f3a4b5c6d/snapshots"))
+
+			_, err = client.TakeInstanceGroupSnapshots("cf-warden", "database")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
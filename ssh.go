@@ -0,0 +1,180 @@
+package gogobosh
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHTarget selects the instances to set up SSH access on
+type SSHTarget struct {
+	InstanceGroup string
+	// IDs restricts the target to the given instance IDs or indexes, every
+	// instance of the group is targeted when empty
+	IDs []string
+}
+
+// SSHOptions configures how long to wait for the SSH setup and cleanup tasks
+type SSHOptions struct {
+	// TaskWaitOptions bounds the wait for each SSH task, which defaults to 5 minutes
+	TaskWaitOptions
+}
+
+// SSHSession holds the ephemeral user and key the director provisioned on the
+// target instances. It must be passed to CleanupSSH once done.
+type SSHSession struct {
+	Deployment string
+	Target     SSHTarget
+	Username   string
+	// PrivateKey is the PEM encoded private key of the ephemeral user
+	PrivateKey []byte
+	Signer     ssh.Signer
+	Hosts      []SSHHost
+}
+
+// ClientConfig returns the configuration to connect to the host as the
+// ephemeral user, trusting only the host key reported by the director
+func (s *SSHSession) ClientConfig(host SSHHost) (*ssh.ClientConfig, error) {
+	if host.HostPublicKey == "" {
+		return nil, fmt.Errorf("no host public key for %s/%s", host.Job, host.ID)
+	}
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(host.HostPublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing host public key of %s/%s: %w", host.Job, host.ID, err)
+	}
+	return &ssh.ClientConfig{
+		User:            s.Username,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(s.Signer)},
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+	}, nil
+}
+
+// SetupSSH generates an ephemeral key pair and has the director create a user
+// authorized with it on every targeted instance
+func (c *Client) SetupSSH(deployment string, target SSHTarget, opts SSHOptions) (*SSHSession, error) {
+	session, publicKey, err := newSSHSession(deployment, target)
+	if err != nil {
+		return nil, fmt.Errorf("error generating SSH key pair: %w", err)
+	}
+
+	task, err := c.sshCommand(deployment, target, "setup", map[string]string{
+		"user":       session.Username,
+		"public_key": publicKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error setting up SSH on %s: %w", target.InstanceGroup, err)
+	}
+
+	task, err = c.WaitUntilDoneWithInterval(task, opts.timeout(time.Minute*5), opts.pollInterval())
+	if err == nil {
+		session.Hosts, err = c.getSSHHosts(task.ID)
+	}
+	if err != nil {
+		// The user may have been created on some of the instances
+		_ = c.CleanupSSH(session, opts)
+		return nil, fmt.Errorf("error setting up SSH on %s: %w", target.InstanceGroup, err)
+	}
+	return session, nil
+}
+
+// CleanupSSH removes the ephemeral user of the session from the targeted instances
+func (c *Client) CleanupSSH(session *SSHSession, opts SSHOptions) error {
+	task, err := c.sshCommand(session.Deployment, session.Target, "cleanup", map[string]string{
+		"user_regex": "^" + session.Username,
+	})
+	if err != nil {
+		return fmt.Errorf("error cleaning up SSH on %s: %w", session.Target.InstanceGroup, err)
+	}
+
+	_, err = c.WaitUntilDoneWithInterval(task, opts.timeout(time.Minute*5), opts.pollInterval())
+	if err != nil {
+		return fmt.Errorf("error waiting for SSH cleanup task to complete: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) sshCommand(deployment string, target SSHTarget, command string, params map[string]string) (Task, error) {
+	r := c.NewRequest("POST", "/deployments/"+deployment+"/ssh")
+	in := struct {
+		Command        string `json:"command"`
+		DeploymentName string `json:"deployment_name"`
+		Target         struct {
+			Job     string   `json:"job"`
+			Indexes []string `json:"indexes"`
+			IDs     []string `json:"ids"`
+		} `json:"target"`
+		Params map[string]string `json:"params"`
+	}{
+		Command:        command,
+		DeploymentName: deployment,
+		Params:         params,
+	}
+	in.Target.Job = target.InstanceGroup
+	in.Target.Indexes = append([]string{}, target.IDs...)
+	in.Target.IDs = append([]string{}, target.IDs...)
+
+	b, err := json.Marshal(&in)
+	if err != nil {
+		return Task{}, fmt.Errorf("error marshalling the SSH %s request: %w", command, err)
+	}
+	r.body = bytes.NewBuffer(b)
+	r.header["Content-Type"] = "application/json"
+
+	var task Task
+	err = c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, err
+	}
+	return task, nil
+}
+
+func (c *Client) getSSHHosts(taskID int) ([]SSHHost, error) {
+	output, err := c.GetTaskResult(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting SSH task %d result: %w", taskID, err)
+	}
+	var hosts []SSHHost
+	err = json.Unmarshal([]byte(strings.Join(output, "")), &hosts)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling SSH task %d result: %w", taskID, err)
+	}
+	return hosts, nil
+}
+
+func newSSHSession(deployment string, target SSHTarget) (*SSHSession, string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, "", err
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	suffix := make([]byte, 8)
+	if _, err = rand.Read(suffix); err != nil {
+		return nil, "", err
+	}
+
+	session := &SSHSession{
+		Deployment: deployment,
+		Target:     target,
+		Username:   "bosh_" + hex.EncodeToString(suffix),
+		PrivateKey: pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		}),
+		Signer: signer,
+	}
+	publicKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+	return session, publicKey, nil
+}
@@ -0,0 +1,234 @@
+package gogobosh
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/net/context"
+)
+
+// SSHGateway is a jumpbox through which the instances are reached
+type SSHGateway struct {
+	// Host is the address of the gateway, the port defaults to 22
+	Host     string
+	Username string
+	// PrivateKey is the PEM encoded key of the gateway user, the ephemeral
+	// session key is used when empty
+	PrivateKey []byte
+	// HostKeyCallback verifies the key of the gateway and is required
+	HostKeyCallback ssh.HostKeyCallback
+}
+
+// SSHRunOptions configures how a command is run across instances
+type SSHRunOptions struct {
+	SSHOptions
+	// Parallelism is how many instances run the command at once, defaults to 10
+	Parallelism int
+	// HostTimeout bounds connecting to and running the command on a single
+	// instance, and connecting to the gateway. Defaults to 5 minutes.
+	HostTimeout time.Duration
+	// Port is the SSH port of the instances, defaults to 22
+	Port int
+	// Gateway, if set, is used to reach the instances
+	Gateway *SSHGateway
+}
+
+func (o SSHRunOptions) parallelism() int {
+	if o.Parallelism <= 0 {
+		return 10
+	}
+	return o.Parallelism
+}
+
+func (o SSHRunOptions) hostTimeout() time.Duration {
+	if o.HostTimeout <= 0 {
+		return time.Minute * 5
+	}
+	return o.HostTimeout
+}
+
+func (o SSHRunOptions) port() int {
+	if o.Port <= 0 {
+		return 22
+	}
+	return o.Port
+}
+
+// SSHResult is the outcome of running a command on a single instance
+type SSHResult struct {
+	Host     SSHHost
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	// Error is set when the command could not be run or did not complete,
+	// a non-zero exit code alone is not an error
+	Error error
+}
+
+// RunSSHCommand sets up SSH access on the targeted instances, runs the
+// command on each of them and returns their results in the order the
+// director reported the instances. The SSH users are always cleaned up.
+func (c *Client) RunSSHCommand(deployment string, target SSHTarget, command string, opts SSHRunOptions) (results []SSHResult, err error) {
+	session, err := c.SetupSSH(deployment, target, opts.SSHOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		cleanupErr := c.CleanupSSH(session, opts.SSHOptions)
+		if err == nil {
+			err = cleanupErr
+		}
+	}()
+
+	var gateway *ssh.Client
+	if opts.Gateway != nil {
+		gateway, err = dialSSHGateway(session, opts.Gateway, opts.hostTimeout())
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = gateway.Close() }()
+	}
+
+	results = make([]SSHResult, len(session.Hosts))
+	sem := make(chan struct{}, opts.parallelism())
+	var wg sync.WaitGroup
+	for i, host := range session.Hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, host SSHHost) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = runSSHCommandOnHost(session, gateway, host, command, opts)
+		}(i, host)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+func dialSSHGateway(session *SSHSession, gw *SSHGateway, timeout time.Duration) (*ssh.Client, error) {
+	if gw.HostKeyCallback == nil {
+		return nil, errors.New("a host key callback is required for the SSH gateway")
+	}
+	signer := session.Signer
+	if len(gw.PrivateKey) > 0 {
+		var err error
+		signer, err = ssh.ParsePrivateKey(gw.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the SSH gateway private key: %w", err)
+		}
+	}
+
+	addr := gw.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+	config := &ssh.ClientConfig{
+		User:            gw.Username,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: gw.HostKeyCallback,
+		Timeout:         timeout,
+	}
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to SSH gateway %s: %w", addr, err)
+	}
+	// ssh.Dial only bounds the TCP connect, the deadline also bounds the handshake
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("error connecting to SSH gateway %s: %w", addr, err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+func runSSHCommandOnHost(session *SSHSession, gateway *ssh.Client, host SSHHost, command string, opts SSHRunOptions) SSHResult {
+	result := SSHResult{Host: host}
+	if host.Status != "success" {
+		result.Error = fmt.Errorf("SSH setup on %s/%s failed with status %q", host.Job, host.ID, host.Status)
+		return result
+	}
+
+	config, err := session.ClientConfig(host)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	config.Timeout = opts.hostTimeout()
+
+	// The timeout covers everything from dialing the host to the end of the command
+	ctx, cancel := context.WithTimeout(context.Background(), opts.hostTimeout())
+	defer cancel()
+	timedOut := func() error {
+		return fmt.Errorf("timed out running command on %s/%s after %s", host.Job, host.ID, opts.hostTimeout())
+	}
+
+	addr := net.JoinHostPort(host.IP, strconv.Itoa(opts.port()))
+	var conn net.Conn
+	if gateway != nil {
+		conn, err = gateway.DialContext(ctx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			result.Error = timedOut()
+			return result
+		}
+		result.Error = fmt.Errorf("error connecting to %s/%s at %s: %w", host.Job, host.ID, addr, err)
+		return result
+	}
+
+	// Closing the connection aborts whatever is still in progress on the host
+	deadline, _ := ctx.Deadline()
+	timer := time.AfterFunc(time.Until(deadline), func() { _ = conn.Close() })
+	defer timer.Stop()
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		_ = conn.Close()
+		if !timer.Stop() {
+			result.Error = timedOut()
+			return result
+		}
+		result.Error = fmt.Errorf("error establishing SSH connection to %s/%s: %w", host.Job, host.ID, err)
+		return result
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer func() { _ = client.Close() }()
+
+	s, err := client.NewSession()
+	if err != nil {
+		result.Error = fmt.Errorf("error opening SSH session on %s/%s: %w", host.Job, host.ID, err)
+		return result
+	}
+	defer func() { _ = s.Close() }()
+
+	var stdout, stderr bytes.Buffer
+	s.Stdout = &stdout
+	s.Stderr = &stderr
+	err = s.Run(command)
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+
+	var exitErr *ssh.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitStatus()
+	case !timer.Stop():
+		result.Error = timedOut()
+	default:
+		result.Error = fmt.Errorf("error running command on %s/%s: %w", host.Job, host.ID, err)
+	}
+	return result
+}
@@ -0,0 +1,279 @@
+package gogobosh_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+)
+
+// forwardedConns counts the connections fake SSH servers forwarded as a gateway
+var forwardedConns int32
+
+// fakeSSHServer accepts any public key and runs a tiny set of commands:
+// "echo <text>", "exit <code>" and "sleep". It also forwards direct-tcpip
+// channels so it can act as a gateway.
+func fakeSSHServer(hostKey ssh.Signer) net.Listener {
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(ssh.ConnMetadata, ssh.PublicKey) (*ssh.Permissions, error) {
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeSSHConn(conn, config)
+		}
+	}()
+	return listener
+}
+
+func serveFakeSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() == "direct-tcpip" {
+			go forwardFakeSSHChannel(newChannel)
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer func() { _ = channel.Close() }()
+			for req := range requests {
+				if req.Type != "exec" {
+					_ = req.Reply(false, nil)
+					continue
+				}
+				_ = req.Reply(true, nil)
+				command := string(req.Payload[4:])
+				status := 0
+				switch {
+				case strings.HasPrefix(command, "echo "):
+					_, _ = fmt.Fprintln(channel, strings.TrimPrefix(command, "echo "))
+				case strings.HasPrefix(command, "exit "):
+					status, _ = strconv.Atoi(strings.TrimPrefix(command, "exit "))
+					_, _ = fmt.Fprintln(channel.Stderr(), "failing")
+				case command == "sleep":
+					time.Sleep(time.Second * 2)
+				}
+				payload := make([]byte, 4)
+				binary.BigEndian.PutUint32(payload, uint32(status))
+				_, _ = channel.SendRequest("exit-status", false, payload)
+				return
+			}
+		}()
+	}
+}
+
+func forwardFakeSSHChannel(newChannel ssh.NewChannel) {
+	var target struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &target); err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	conn, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+	if err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	atomic.AddInt32(&forwardedConns, 1)
+	go ssh.DiscardRequests(requests)
+	go func() {
+		_, _ = io.Copy(channel, conn)
+		_ = channel.CloseWrite()
+	}()
+	_, _ = io.Copy(conn, channel)
+	_ = conn.Close()
+}
+
+// silentListener accepts connections and never answers
+func silentListener() net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	go func() {
+		var conns []net.Conn
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				break
+			}
+			conns = append(conns, conn)
+		}
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	}()
+	return listener
+}
+
+var _ = Describe("SSH exec", func() {
+	Describe("Test running commands over SSH", func() {
+		var (
+			client   *Client
+			listener net.Listener
+			port     int
+			hostKey  ssh.Signer
+		)
+
+		BeforeEach(func() {
+			hostKey = newHostKey()
+			listener = fakeSSHServer(hostKey)
+			port = listener.Addr().(*net.TCPAddr).Port
+
+			hostKeyString := authorizedKey(hostKey.PublicKey())
+			hosts := fmt.Sprintf(`[
+				{"status":"success","ip":"127.0.0.1","host_public_key":"%s","index":0,"job":"diego_cell","id":"a1"},
+				{"status":"success","ip":"127.0.0.1","host_public_key":"%s","index":1,"job":"diego_cell","id":"b2"},
+				{"status":"failure","ip":"127.0.0.1","host_public_key":"","index":2,"job":"diego_cell","id":"c3"}
+			]`, hostKeyString, hostKeyString)
+			setupMockRoutes([]MockRoute{
+				{"POST", "/deployments/cf-warden/ssh", "", "/tasks/8"},
+				{"GET", "/tasks/8", sshTask, ""},
+				{"GET", "/tasks/8/output", strings.ReplaceAll(hosts, "\n", ""), ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			_ = listener.Close()
+			teardown()
+		})
+
+		It("runs the command on every instance and cleans up", func() {
+			results, err := client.RunSSHCommand("cf-warden", SSHTarget{InstanceGroup: "diego_cell"}, "echo hello", SSHRunOptions{
+				SSHOptions:  SSHOptions{TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10}},
+				Parallelism: 2,
+				Port:        port,
+			})
+			Expect(err).Should(BeNil())
+			Expect(results).Should(HaveLen(3))
+			for _, result := range results[:2] {
+				Expect(result.Error).Should(BeNil())
+				Expect(result.Stdout).Should(Equal("hello\n"))
+				Expect(result.ExitCode).Should(Equal(0))
+			}
+			Expect(results[0].Host.ID).Should(Equal("a1"))
+			Expect(results[1].Host.ID).Should(Equal("b2"))
+			Expect(results[2].Error).Should(HaveOccurred())
+
+			Expect(receivedRequests["POST /deployments/cf-warden/ssh"].Body).Should(ContainSubstring(`"command":"cleanup"`))
+		})
+
+		It("reports exit codes and per host timeouts", func() {
+			results, err := client.RunSSHCommand("cf-warden", SSHTarget{InstanceGroup: "diego_cell"}, "exit 3", SSHRunOptions{
+				SSHOptions: SSHOptions{TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10}},
+				Port:       port,
+			})
+			Expect(err).Should(BeNil())
+			Expect(results[0].Error).Should(BeNil())
+			Expect(results[0].ExitCode).Should(Equal(3))
+			Expect(results[0].Stderr).Should(Equal("failing\n"))
+
+			results, err = client.RunSSHCommand("cf-warden", SSHTarget{InstanceGroup: "diego_cell"}, "sleep", SSHRunOptions{
+				SSHOptions:  SSHOptions{TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10}},
+				HostTimeout: time.Millisecond * 200,
+				Port:        port,
+			})
+			Expect(err).Should(BeNil())
+			Expect(results[0].Error).Should(MatchError(ContainSubstring("timed out")))
+			Expect(receivedRequests["POST /deployments/cf-warden/ssh"].Body).Should(ContainSubstring(`"command":"cleanup"`))
+		})
+
+		It("reaches the instances through a gateway", func() {
+			before := atomic.LoadInt32(&forwardedConns)
+			results, err := client.RunSSHCommand("cf-warden", SSHTarget{InstanceGroup: "diego_cell"}, "echo hello", SSHRunOptions{
+				SSHOptions: SSHOptions{TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10}},
+				Port:       port,
+				Gateway: &SSHGateway{
+					Host:            listener.Addr().String(),
+					Username:        "jumpbox",
+					HostKeyCallback: ssh.FixedHostKey(hostKey.PublicKey()),
+				},
+			})
+			Expect(err).Should(BeNil())
+			Expect(results[0].Error).Should(BeNil())
+			Expect(results[0].Stdout).Should(Equal("hello\n"))
+			Expect(results[1].Stdout).Should(Equal("hello\n"))
+			Expect(atomic.LoadInt32(&forwardedConns) - before).Should(Equal(int32(2)))
+		})
+
+		It("times out on a gateway that never answers", func() {
+			silent := silentListener()
+			defer func() { _ = silent.Close() }()
+
+			start := time.Now()
+			_, err := client.RunSSHCommand("cf-warden", SSHTarget{InstanceGroup: "diego_cell"}, "echo hello", SSHRunOptions{
+				SSHOptions:  SSHOptions{TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10}},
+				HostTimeout: time.Millisecond * 200,
+				Port:        port,
+				Gateway: &SSHGateway{
+					Host:            silent.Addr().String(),
+					Username:        "jumpbox",
+					HostKeyCallback: ssh.FixedHostKey(hostKey.PublicKey()),
+				},
+			})
+			Expect(err).Should(MatchError(ContainSubstring("error connecting to SSH gateway")))
+			Expect(time.Since(start)).Should(BeNumerically("<", time.Second*2))
+			Expect(receivedRequests["POST /deployments/cf-warden/ssh"].Body).Should(ContainSubstring(`"command":"cleanup"`))
+		})
+
+		It("times out on instances that never answer through the gateway", func() {
+			silent := silentListener()
+			defer func() { _ = silent.Close() }()
+
+			start := time.Now()
+			results, err := client.RunSSHCommand("cf-warden", SSHTarget{InstanceGroup: "diego_cell"}, "echo hello", SSHRunOptions{
+				SSHOptions:  SSHOptions{TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10}},
+				HostTimeout: time.Millisecond * 200,
+				Port:        silent.Addr().(*net.TCPAddr).Port,
+				Gateway: &SSHGateway{
+					Host:            listener.Addr().String(),
+					Username:        "jumpbox",
+					HostKeyCallback: ssh.FixedHostKey(hostKey.PublicKey()),
+				},
+			})
+			Expect(err).Should(BeNil())
+			Expect(results[0].Error).Should(MatchError(ContainSubstring("timed out")))
+			Expect(results[1].Error).Should(MatchError(ContainSubstring("timed out")))
+			Expect(time.Since(start)).Should(BeNumerically("<", time.Second*2))
+		})
+	})
+})
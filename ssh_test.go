@@ -0,0 +1,104 @@
+package gogobosh_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+)
+
+func newHostKey() ssh.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	signer, err := ssh.NewSignerFromKey(key)
+	Expect(err).NotTo(HaveOccurred())
+	return signer
+}
+
+func authorizedKey(key ssh.PublicKey) string {
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+}
+
+var _ = Describe("SSH", func() {
+	Describe("Test SSH setup and cleanup", func() {
+		var (
+			client  *Client
+			hostKey ssh.Signer
+		)
+
+		BeforeEach(func() {
+			hostKey = newHostKey()
+			setupMockRoutes([]MockRoute{
+				{"POST", "/deployments/cf-warden/ssh", "", "/tasks/8"},
+				{"GET", "/tasks/8", sshTask, ""},
+				{"GET", "/tasks/8/output", fmt.Sprintf(sshHostsTemplate, "10.244.0.142", authorizedKey(hostKey.PublicKey())), ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("can set up and clean up SSH access", func() {
+			opts := SSHOptions{TaskWaitOptions: TaskWaitOptions{PollInterval: time.Millisecond * 10}}
+			session, err := client.SetupSSH("cf-warden", SSHTarget{
+				InstanceGroup: "doppler_z1",
+				IDs:           []string{"4a9278c8"},
+			}, opts)
+			Expect(err).Should(BeNil())
+			Expect(session.Username).Should(HavePrefix("bosh_"))
+			Expect(string(session.PrivateKey)).Should(ContainSubstring("RSA PRIVATE KEY"))
+			Expect(session.Hosts).Should(HaveLen(1))
+			Expect(session.Hosts[0].Status).Should(Equal("success"))
+			Expect(session.Hosts[0].IP).Should(Equal("10.244.0.142"))
+			Expect(session.Hosts[0].Job).Should(Equal("doppler_z1"))
+			Expect(session.Hosts[0].ID).Should(Equal("4a9278c8"))
+
+			var setup struct {
+				Command        string `json:"command"`
+				DeploymentName string `json:"deployment_name"`
+				Target         struct {
+					Job string   `json:"job"`
+					IDs []string `json:"ids"`
+				} `json:"target"`
+				Params map[string]string `json:"params"`
+			}
+			Expect(json.Unmarshal([]byte(receivedRequests["POST /deployments/cf-warden/ssh"].Body), &setup)).To(Succeed())
+			Expect(setup.Command).Should(Equal("setup"))
+			Expect(setup.DeploymentName).Should(Equal("cf-warden"))
+			Expect(setup.Target.Job).Should(Equal("doppler_z1"))
+			Expect(setup.Target.IDs).Should(Equal([]string{"4a9278c8"}))
+			Expect(setup.Params["user"]).Should(Equal(session.Username))
+			Expect(setup.Params["public_key"]).Should(Equal(authorizedKey(session.Signer.PublicKey())))
+
+			config, err := session.ClientConfig(session.Hosts[0])
+			Expect(err).Should(BeNil())
+			Expect(config.User).Should(Equal(session.Username))
+			Expect(config.HostKeyCallback("10.244.0.142:22", nil, hostKey.PublicKey())).To(Succeed())
+			Expect(config.HostKeyCallback("10.244.0.142:22", nil, newHostKey().PublicKey())).NotTo(Succeed())
+
+			Expect(client.CleanupSSH(session, opts)).To(Succeed())
+			var cleanup struct {
+				Command string            `json:"command"`
+				Params  map[string]string `json:"params"`
+			}
+			Expect(json.Unmarshal([]byte(receivedRequests["POST /deployments/cf-warden/ssh"].Body), &cleanup)).To(Succeed())
+			Expect(cleanup.Command).Should(Equal("cleanup"))
+			Expect(cleanup.Params["user_regex"]).Should(Equal("^" + session.Username))
+		})
+	})
+})
@@ -0,0 +1,244 @@
+package gogobosh
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ReleaseManifest is the release.MF of a release tarball
+type ReleaseManifest struct {
+	Name               string                   `yaml:"name"`
+	Version            string                   `yaml:"version"`
+	CommitHash         string                   `yaml:"commit_hash"`
+	UncommittedChanges bool                     `yaml:"uncommitted_changes"`
+	Jobs               []ReleaseManifestJob     `yaml:"jobs"`
+	Packages           []ReleaseManifestPackage `yaml:"packages"`
+	CompiledPackages   []ReleaseManifestPackage `yaml:"compiled_packages"`
+}
+
+// ReleaseManifestJob is a job of a release tarball
+type ReleaseManifestJob struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Fingerprint string `yaml:"fingerprint"`
+	SHA1        string `yaml:"sha1"`
+}
+
+// ReleaseManifestPackage is a source or compiled package of a release tarball
+type ReleaseManifestPackage struct {
+	Name         string   `yaml:"name"`
+	Version      string   `yaml:"version"`
+	Fingerprint  string   `yaml:"fingerprint"`
+	SHA1         string   `yaml:"sha1"`
+	Dependencies []string `yaml:"dependencies"`
+	Stemcell     string   `yaml:"stemcell,omitempty"`
+}
+
+// StemcellManifest is the stemcell.MF of a stemcell tarball
+type StemcellManifest struct {
+	Name            string                 `yaml:"name"`
+	Version         string                 `yaml:"version"`
+	OperatingSystem string                 `yaml:"operating_system"`
+	SHA1            string                 `yaml:"sha1"`
+	CloudProperties map[string]interface{} `yaml:"cloud_properties"`
+}
+
+// ReadReleaseManifest returns the release.MF of the release tarball at path
+func ReadReleaseManifest(path string) (ReleaseManifest, error) {
+	b, err := readTarballFile(path, "release.MF")
+	if err != nil {
+		return ReleaseManifest{}, err
+	}
+	var manifest ReleaseManifest
+	err = yaml.Unmarshal(b, &manifest)
+	if err != nil {
+		return ReleaseManifest{}, fmt.Errorf("error parsing release.MF: %w", err)
+	}
+	return manifest, nil
+}
+
+// ReadStemcellManifest returns the stemcell.MF of the stemcell tarball at path
+func ReadStemcellManifest(path string) (StemcellManifest, error) {
+	b, err := readTarballFile(path, "stemcell.MF")
+	if err != nil {
+		return StemcellManifest{}, err
+	}
+	var manifest StemcellManifest
+	err = yaml.Unmarshal(b, &manifest)
+	if err != nil {
+		return StemcellManifest{}, fmt.Errorf("error parsing stemcell.MF: %w", err)
+	}
+	return manifest, nil
+}
+
+// UploadReleaseFileIfMissing uploads the release tarball at path unless the
+// director already has its version. Packages the director already has are
+// left out of the upload, unless the options ask for a fix. It returns
+// ErrAlreadyUploaded when the upload was skipped.
+func (c *Client) UploadReleaseFileIfMissing(path string, opts UploadOptions) (Task, error) {
+	manifest, err := ReadReleaseManifest(path)
+	if err != nil {
+		return Task{}, err
+	}
+	check := opts
+	check.Name, check.Version = manifest.Name, manifest.Version
+	err = c.skipUpload(true, check)
+	if err != nil {
+		return Task{}, err
+	}
+	if opts.Fix || len(manifest.Packages) == 0 {
+		return c.UploadReleaseFileWithOptions(path, opts)
+	}
+
+	raw, err := readTarballFile(path, "release.MF")
+	if err != nil {
+		return Task{}, err
+	}
+	fingerprints, err := c.matchPackages(raw)
+	if err != nil {
+		return Task{}, err
+	}
+	skip := map[string]bool{}
+	for _, pkg := range manifest.Packages {
+		if containsString(fingerprints, pkg.Fingerprint) {
+			skip["packages/"+pkg.Name+".tgz"] = true
+		}
+	}
+	if len(skip) == 0 {
+		return c.UploadReleaseFileWithOptions(path, opts)
+	}
+
+	repacked, err := repackTarball(path, skip)
+	if err != nil {
+		return Task{}, err
+	}
+	defer func() { _ = os.Remove(repacked) }()
+	return c.UploadReleaseFileWithOptions(repacked, opts)
+}
+
+// UploadStemcellFileIfMissing uploads the stemcell tarball at path unless
+// the director already has its version. It returns ErrAlreadyUploaded when
+// the upload was skipped.
+func (c *Client) UploadStemcellFileIfMissing(path string, opts UploadOptions) (Task, error) {
+	manifest, err := ReadStemcellManifest(path)
+	if err != nil {
+		return Task{}, err
+	}
+	opts.Name, opts.Version = manifest.Name, manifest.Version
+	return c.UploadStemcellFileWithOptions(path, opts)
+}
+
+// matchPackages returns the fingerprints of the packages of the release
+// manifest the director already has
+func (c *Client) matchPackages(manifest []byte) ([]string, error) {
+	r := c.NewRequest("POST", "/packages/matches")
+	r.body = bytes.NewReader(manifest)
+	r.header["Content-Type"] = "text/yaml"
+
+	resp, err := c.DoRequest(r)
+	if err != nil {
+		return []string{}, fmt.Errorf("error matching packages: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var fingerprints []string
+	err = json.NewDecoder(resp.Body).Decode(&fingerprints)
+	if err != nil {
+		return []string{}, fmt.Errorf("error unmarshalling package matches: %w", err)
+	}
+	return fingerprints, nil
+}
+
+// readTarballFile returns the content of the named file of a gzipped tarball
+func readTarballFile(path, name string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening tarball: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading tarball %s: %w", path, err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("tarball %s has no %s", path, name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading tarball %s: %w", path, err)
+		}
+		if strings.TrimPrefix(header.Name, "./") == name {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// repackTarball writes a copy of the gzipped tarball at path without the
+// skipped files to a temporary file and returns its path
+func repackTarball(path string, skip map[string]bool) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error opening tarball: %w", err)
+	}
+	defer func() { _ = src.Close() }()
+	gzr, err := gzip.NewReader(src)
+	if err != nil {
+		return "", fmt.Errorf("error reading tarball %s: %w", path, err)
+	}
+
+	dst, err := os.CreateTemp("", "gogobosh-release-*.tgz")
+	if err != nil {
+		return "", fmt.Errorf("error creating repacked tarball: %w", err)
+	}
+	gzw := gzip.NewWriter(dst)
+	tw := tar.NewWriter(gzw)
+
+	err = func() error {
+		tr := tar.NewReader(gzr)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if skip[strings.TrimPrefix(header.Name, "./")] {
+				continue
+			}
+			err = tw.WriteHeader(header)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(tw, tr)
+			if err != nil {
+				return err
+			}
+		}
+	}()
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gzw.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(dst.Name())
+		return "", fmt.Errorf("error repacking tarball %s: %w", path, err)
+	}
+	return dst.Name(), nil
+}
@@ -0,0 +1,173 @@
+package gogobosh_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func writeTarball(dir, name string, files map[string]string) string {
+	path := filepath.Join(dir, name)
+	Expect(os.WriteFile(path, []byte(logsTarball(files)), 0644)).To(Succeed())
+	return path
+}
+
+func tarballFiles(content string) []string {
+	gz, err := gzip.NewReader(strings.NewReader(content))
+	Expect(err).Should(BeNil())
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		Expect(err).Should(BeNil())
+		names = append(names, header.Name)
+	}
+}
+
+var _ = Describe("Tarball", func() {
+	var dir, releaseTarball, stemcellTarball string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "gogobosh-tarball")
+		Expect(err).NotTo(HaveOccurred())
+
+		releaseTarball = writeTarball(dir, "nginx.tgz", map[string]string{
+			"./release.MF":         nginxReleaseMF,
+			"./jobs/nginx.tgz":     "nginx job",
+			"./packages/nginx.tgz": "nginx package",
+			"./packages/pcre.tgz":  "pcre package",
+		})
+		stemcellTarball = writeTarball(dir, "stemcell.tgz", map[string]string{
+			"stemcell.MF": stemcellMF,
+			"image":       "image",
+		})
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	Describe("Test reading manifests", func() {
+		It("can read a release manifest", func() {
+			manifest, err := ReadReleaseManifest(releaseTarball)
+			Expect(err).Should(BeNil())
+			Expect(manifest.Name).Should(Equal("nginx"))
+			Expect(manifest.Version).Should(Equal("1.21.6"))
+			Expect(manifest.Jobs[0].Name).Should(Equal("nginx"))
+			Expect(manifest.Packages).Should(HaveLen(2))
+			Expect(manifest.Packages[0].Dependencies).Should(Equal([]string{"pcre"}))
+		})
+
+		It("can read a stemcell manifest", func() {
+			manifest, err := ReadStemcellManifest(stemcellTarball)
+			Expect(err).Should(BeNil())
+			Expect(manifest.Name).Should(Equal("bosh-warden-boshlite-ubuntu-trusty-go_agent"))
+			Expect(manifest.Version).Should(Equal("3126"))
+			Expect(manifest.OperatingSystem).Should(Equal("ubuntu-trusty"))
+		})
+
+		It("fails for a tarball without a manifest", func() {
+			_, err := ReadReleaseManifest(stemcellTarball)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("Test uploads of missing tarballs", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"GET", "/releases", releases, ""},
+				{"GET", "/stemcells", stemcells, ""},
+				{"POST", "/packages/matches", packageMatches, ""},
+				{"POST", "/releases", uploadTask, ""},
+				{"POST", "/stemcells", uploadTask, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("uploads a release without the packages the director has", func() {
+			task, err := client.UploadReleaseFileIfMissing(releaseTarball, UploadOptions{})
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(7))
+
+			matches := receivedRequests["POST /packages/matches"]
+			Expect(matches.Header.Get("Content-Type")).Should(Equal("text/yaml"))
+			Expect(matches.Body).Should(Equal(nginxReleaseMF))
+
+			upload := receivedRequests["POST /releases"]
+			Expect(upload.Header.Get("Content-Type")).Should(Equal("application/x-compressed"))
+			Expect(tarballFiles(upload.Body)).Should(ConsistOf("./release.MF", "./jobs/nginx.tgz", "./packages/pcre.tgz"))
+		})
+
+		It("uploads the whole release when fixing", func() {
+			_, err := client.UploadReleaseFileIfMissing(releaseTarball, UploadOptions{Fix: true})
+			Expect(err).Should(BeNil())
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /packages/matches"))
+			Expect(tarballFiles(receivedRequests["POST /releases"].Body)).Should(HaveLen(4))
+		})
+
+		It("skips a release that is already uploaded", func() {
+			path := writeTarball(dir, "cpi.tgz", map[string]string{
+				"release.MF": "name: bosh-warden-cpi\nversion: 28\n",
+			})
+			_, err := client.UploadReleaseFileIfMissing(path, UploadOptions{})
+			Expect(err).Should(MatchError(ErrAlreadyUploaded))
+			Expect(err.Error()).Should(Equal("release bosh-warden-cpi/28 is already uploaded"))
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /releases"))
+		})
+
+		It("rebases a release that is already uploaded", func() {
+			path := writeTarball(dir, "cpi.tgz", map[string]string{
+				"release.MF": "name: bosh-warden-cpi\nversion: 28\n",
+			})
+			task, err := client.UploadReleaseFileIfMissing(path, UploadOptions{Rebase: true})
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(7))
+			Expect(receivedRequests["POST /releases"].Query).Should(HaveKeyWithValue("rebase", []string{"true"}))
+		})
+
+		It("skips a stemcell that is already uploaded, even when rebasing", func() {
+			_, err := client.UploadStemcellFileIfMissing(stemcellTarball, UploadOptions{Rebase: true})
+			Expect(err).Should(MatchError(ErrAlreadyUploaded))
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /stemcells"))
+		})
+
+		It("skips a stemcell that is already uploaded", func() {
+			_, err := client.UploadStemcellFileIfMissing(stemcellTarball, UploadOptions{})
+			Expect(err).Should(MatchError(ErrAlreadyUploaded))
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /stemcells"))
+		})
+
+		It("uploads a missing stemcell", func() {
+			path := writeTarball(dir, "stemcell-3127.tgz", map[string]string{
+				"stemcell.MF": strings.Replace(stemcellMF, `"3126"`, `"3127"`, 1),
+			})
+			task, err := client.UploadStemcellFileIfMissing(path, UploadOptions{})
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(7))
+			Expect(receivedRequests).Should(HaveKey("POST /stemcells"))
+		})
+	})
+})
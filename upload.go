@@ -0,0 +1,311 @@
+package gogobosh
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+)
+
+// UploadOptions configure release and stemcell uploads
+type UploadOptions struct {
+	// SHA1 is the digest the director verifies a remote tarball against,
+	// either a bare SHA1 or a multi-digest like "sha256:...;sha1:..."
+	SHA1 string
+	// Fix re-uploads a release or stemcell that is already present to repair it
+	Fix bool
+	// Rebase creates a new version of a release even if its version exists
+	Rebase bool
+	// Name and Version, when both set, skip the upload if the director
+	// already has that release or stemcell version, unless Fix is set or a
+	// release is rebased
+	Name    string
+	Version string
+	// Progress is called as a local tarball is sent, with the number of
+	// bytes sent, the total size and the rate in bytes per second
+	Progress func(sent, total int64, rate float64)
+}
+
+// ErrAlreadyUploaded is returned instead of a task when an upload is skipped
+// because the director already has the release or stemcell version
+var ErrAlreadyUploaded = errors.New("already uploaded")
+
+// checkUploaded reports whether an upload is skipped when the director
+// already has the version. It is not when fixing, or when rebasing a release.
+func (o UploadOptions) checkUploaded(release bool) bool {
+	if release {
+		return !o.Fix && !o.Rebase
+	}
+	return !o.Fix
+}
+
+func (o UploadOptions) query(release bool) url.Values {
+	query := url.Values{}
+	if o.Fix {
+		query.Set("fix", "true")
+	}
+	if release && o.Rebase {
+		query.Set("rebase", "true")
+	}
+	return query
+}
+
+// UploadReleaseReader streams a release tarball of the given size to the
+// given BOSH without buffering it in memory
+func (c *Client) UploadReleaseReader(r io.Reader, size int64) (Task, error) {
+	return c.UploadReleaseReaderWithOptions(r, size, UploadOptions{})
+}
+
+// UploadReleaseReaderWithOptions streams a release tarball of the given size
+// to the given BOSH. It returns ErrAlreadyUploaded when the upload was skipped.
+func (c *Client) UploadReleaseReaderWithOptions(r io.Reader, size int64, opts UploadOptions) (Task, error) {
+	err := c.skipUpload(true, opts)
+	if err != nil {
+		return Task{}, err
+	}
+	task, err := c.uploadTarball("/releases", r, size, opts.query(true), opts.Progress)
+	if err != nil {
+		return Task{}, fmt.Errorf("error uploading release: %w", err)
+	}
+	return task, nil
+}
+
+// UploadReleaseFile streams the release tarball at path to the given BOSH
+func (c *Client) UploadReleaseFile(path string) (Task, error) {
+	return c.UploadReleaseFileWithOptions(path, UploadOptions{})
+}
+
+// UploadReleaseFileWithOptions streams the release tarball at path to the
+// given BOSH. It returns ErrAlreadyUploaded when the upload was skipped.
+func (c *Client) UploadReleaseFileWithOptions(path string, opts UploadOptions) (Task, error) {
+	f, size, err := openTarball(path)
+	if err != nil {
+		return Task{}, err
+	}
+	defer func() { _ = f.Close() }()
+	return c.UploadReleaseReaderWithOptions(f, size, opts)
+}
+
+// UploadReleaseURL makes the given BOSH download and import the release at
+// the URL. It returns ErrAlreadyUploaded when the upload was skipped.
+func (c *Client) UploadReleaseURL(location string, opts UploadOptions) (Task, error) {
+	err := c.skipUpload(true, opts)
+	if err != nil {
+		return Task{}, err
+	}
+	task, err := c.uploadURL("/releases", location, opts.SHA1, opts.query(true))
+	if err != nil {
+		return Task{}, fmt.Errorf("error uploading release %s: %w", location, err)
+	}
+	return task, nil
+}
+
+// UploadStemcellReader streams a stemcell tarball of the given size to the
+// given BOSH without buffering it in memory
+func (c *Client) UploadStemcellReader(r io.Reader, size int64) (Task, error) {
+	return c.UploadStemcellReaderWithOptions(r, size, UploadOptions{})
+}
+
+// UploadStemcellReaderWithOptions streams a stemcell tarball of the given
+// size to the given BOSH. It returns ErrAlreadyUploaded when the upload was skipped.
+func (c *Client) UploadStemcellReaderWithOptions(r io.Reader, size int64, opts UploadOptions) (Task, error) {
+	err := c.skipUpload(false, opts)
+	if err != nil {
+		return Task{}, err
+	}
+	task, err := c.uploadTarball("/stemcells", r, size, opts.query(false), opts.Progress)
+	if err != nil {
+		return Task{}, fmt.Errorf("error uploading stemcell: %w", err)
+	}
+	return task, nil
+}
+
+// UploadStemcellFile streams the stemcell tarball at path to the given BOSH
+func (c *Client) UploadStemcellFile(path string) (Task, error) {
+	return c.UploadStemcellFileWithOptions(path, UploadOptions{})
+}
+
+// UploadStemcellFileWithOptions streams the stemcell tarball at path to the
+// given BOSH. It returns ErrAlreadyUploaded when the upload was skipped.
+func (c *Client) UploadStemcellFileWithOptions(path string, opts UploadOptions) (Task, error) {
+	f, size, err := openTarball(path)
+	if err != nil {
+		return Task{}, err
+	}
+	defer func() { _ = f.Close() }()
+	return c.UploadStemcellReaderWithOptions(f, size, opts)
+}
+
+// UploadStemcellURL makes the given BOSH download and import the stemcell at
+// the URL. It returns ErrAlreadyUploaded when the upload was skipped.
+func (c *Client) UploadStemcellURL(location string, opts UploadOptions) (Task, error) {
+	err := c.skipUpload(false, opts)
+	if err != nil {
+		return Task{}, err
+	}
+	task, err := c.uploadURL("/stemcells", location, opts.SHA1, opts.query(false))
+	if err != nil {
+		return Task{}, fmt.Errorf("error uploading stemcell %s: %w", location, err)
+	}
+	return task, nil
+}
+
+func (c *Client) uploadURL(path, location, sha1 string, query url.Values) (Task, error) {
+	if sha1 != "" {
+		_, err := parseDigests(sha1)
+		if err != nil {
+			return Task{}, err
+		}
+	}
+	in := struct {
+		Location string `json:"location"`
+		SHA1     string `json:"sha1,omitempty"`
+	}{
+		Location: location,
+		SHA1:     sha1,
+	}
+	b, err := json.Marshal(&in)
+	if err != nil {
+		return Task{}, fmt.Errorf("error marshalling upload request: %w", err)
+	}
+
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	r := c.NewRequest("POST", path)
+	r.body = bytes.NewBuffer(b)
+	r.header["Content-Type"] = "application/json"
+
+	var task Task
+	err = c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, err
+	}
+	return task, nil
+}
+
+func (c *Client) uploadTarball(path string, body io.Reader, size int64, query url.Values, progress func(sent, total int64, rate float64)) (Task, error) {
+	if size <= 0 {
+		return Task{}, fmt.Errorf("invalid tarball size %d", size)
+	}
+	// the limit keeps the transport from closing a body owned by the caller
+	body = io.LimitReader(body, size)
+	if progress != nil {
+		body = &progressReader{r: body, total: size, start: time.Now(), progress: progress}
+	}
+
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	r := c.NewRequest("POST", path)
+	r.body = body
+	r.contentLength = size
+	r.header["Content-Type"] = "application/x-compressed"
+
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, err
+	}
+	return task, nil
+}
+
+// skipUpload returns ErrAlreadyUploaded when the director already has the
+// release or stemcell version and the upload is not forced
+func (c *Client) skipUpload(release bool, opts UploadOptions) error {
+	if !opts.checkUploaded(release) {
+		return nil
+	}
+	kind, has := "stemcell", c.hasStemcell
+	if release {
+		kind, has = "release", c.hasRelease
+	}
+	uploaded, err := has(opts.Name, opts.Version)
+	if err != nil {
+		return err
+	}
+	if uploaded {
+		return fmt.Errorf("%s %s/%s is %w", kind, opts.Name, opts.Version, ErrAlreadyUploaded)
+	}
+	return nil
+}
+
+// hasRelease reports whether the release version is already uploaded, it is
+// false unless both name and version are set
+func (c *Client) hasRelease(name, version string) (bool, error) {
+	if name == "" || version == "" {
+		return false, nil
+	}
+	releases, err := c.GetReleases()
+	if err != nil {
+		return false, err
+	}
+	for _, release := range releases {
+		if release.Name != name {
+			continue
+		}
+		for _, rv := range release.ReleaseVersions {
+			if rv.Version == version {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// hasStemcell reports whether the stemcell version is already uploaded, it
+// is false unless both name and version are set
+func (c *Client) hasStemcell(name, version string) (bool, error) {
+	if name == "" || version == "" {
+		return false, nil
+	}
+	stemcells, err := c.GetStemcells()
+	if err != nil {
+		return false, err
+	}
+	for _, stemcell := range stemcells {
+		if stemcell.Name == name && stemcell.Version == version {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func openTarball(path string) (*os.File, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error opening tarball: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, 0, fmt.Errorf("error reading tarball size: %w", err)
+	}
+	return f, info.Size(), nil
+}
+
+// progressReader reports the number of bytes read through it and the rate
+type progressReader struct {
+	r        io.Reader
+	sent     int64
+	total    int64
+	start    time.Time
+	progress func(sent, total int64, rate float64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.sent += int64(n)
+		var rate float64
+		if elapsed := time.Since(p.start).Seconds(); elapsed > 0 {
+			rate = float64(p.sent) / elapsed
+		}
+		p.progress(p.sent, p.total, rate)
+	}
+	return n, err
+}
@@ -0,0 +1,219 @@
+package gogobosh_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Upload", func() {
+	Describe("Test local uploads", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"POST", "/releases", uploadTask, ""},
+				{"POST", "/stemcells", uploadTask, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("streams a release from a reader", func() {
+			task, err := client.UploadReleaseReader(strings.NewReader("release tarball and more"), 15)
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(7))
+			Expect(task.State).Should(Equal("queued"))
+
+			req := receivedRequests["POST /releases"]
+			Expect(req.Body).Should(Equal("release tarball"))
+			Expect(req.Header.Get("Content-Type")).Should(Equal("application/x-compressed"))
+			Expect(req.Header.Get("Content-Length")).Should(Equal("15"))
+		})
+
+		It("streams a stemcell from a file", func() {
+			dir, err := os.MkdirTemp("", "gogobosh-upload")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = os.RemoveAll(dir) }()
+			path := filepath.Join(dir, "stemcell.tgz")
+			Expect(os.WriteFile(path, []byte("stemcell tarball"), 0644)).To(Succeed())
+
+			task, err := client.UploadStemcellFile(path)
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(7))
+
+			req := receivedRequests["POST /stemcells"]
+			Expect(req.Body).Should(Equal("stemcell tarball"))
+			Expect(req.Header.Get("Content-Type")).Should(Equal("application/x-compressed"))
+			Expect(req.Header.Get("Content-Length")).Should(Equal("16"))
+		})
+
+		It("streams a release from a file", func() {
+			dir, err := os.MkdirTemp("", "gogobosh-upload")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = os.RemoveAll(dir) }()
+			path := filepath.Join(dir, "release.tgz")
+			Expect(os.WriteFile(path, []byte("release tarball"), 0644)).To(Succeed())
+
+			_, err = client.UploadReleaseFile(path)
+			Expect(err).Should(BeNil())
+			Expect(receivedRequests["POST /releases"].Body).Should(Equal("release tarball"))
+		})
+
+		It("fails for a missing file", func() {
+			dir, err := os.MkdirTemp("", "gogobosh-upload")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			_, err = client.UploadReleaseFile(filepath.Join(dir, "missing.tgz"))
+			Expect(err).Should(HaveOccurred())
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /releases"))
+		})
+
+		It("fails when the reader is shorter than its size", func() {
+			_, err := client.UploadStemcellReader(strings.NewReader("short"), 100)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("Test uploads with options", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"POST", "/releases", uploadTask, ""},
+				{"POST", "/stemcells", uploadTask, ""},
+				{"GET", "/releases", releases, ""},
+				{"GET", "/stemcells", stemcells, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("uploads a release from a URL with a multi-digest", func() {
+			digest := "sha256:" + strings.Repeat("ab", 32) + ";sha1:" + strings.Repeat("cd", 20)
+			task, err := client.UploadReleaseURL("https://example.com/nginx.tgz", UploadOptions{SHA1: digest, Fix: true, Rebase: true})
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(7))
+
+			req := receivedRequests["POST /releases"]
+			Expect(req.Body).Should(MatchJSON(`{"location": "https://example.com/nginx.tgz", "sha1": "` + digest + `"}`))
+			Expect(req.Query).Should(HaveKeyWithValue("fix", []string{"true"}))
+			Expect(req.Query).Should(HaveKeyWithValue("rebase", []string{"true"}))
+		})
+
+		It("uploads a stemcell from a URL without rebasing", func() {
+			_, err := client.UploadStemcellURL("https://example.com/stemcell.tgz", UploadOptions{Fix: true, Rebase: true})
+			Expect(err).Should(BeNil())
+
+			req := receivedRequests["POST /stemcells"]
+			Expect(req.Body).Should(MatchJSON(`{"location": "https://example.com/stemcell.tgz"}`))
+			Expect(req.Query).Should(HaveKeyWithValue("fix", []string{"true"}))
+			Expect(req.Query).ShouldNot(HaveKey("rebase"))
+		})
+
+		It("refuses an invalid digest", func() {
+			_, err := client.UploadStemcellURL("https://example.com/stemcell.tgz", UploadOptions{SHA1: "sha256:abc"})
+			Expect(err).Should(HaveOccurred())
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /stemcells"))
+		})
+
+		It("skips releases that are already uploaded", func() {
+			_, err := client.UploadReleaseURL("https://example.com/cpi.tgz", UploadOptions{Name: "bosh-warden-cpi", Version: "28"})
+			Expect(err).Should(MatchError(ErrAlreadyUploaded))
+			Expect(err.Error()).Should(Equal("release bosh-warden-cpi/28 is already uploaded"))
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /releases"))
+
+			task, err := client.UploadReleaseURL("https://example.com/cpi.tgz", UploadOptions{Name: "bosh-warden-cpi", Version: "29"})
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(7))
+		})
+
+		It("skips stemcells that are already uploaded", func() {
+			_, err := client.UploadStemcellReaderWithOptions(strings.NewReader("stemcell"), 8, UploadOptions{
+				Name:    "bosh-warden-boshlite-ubuntu-trusty-go_agent",
+				Version: "3126",
+			})
+			Expect(err).Should(MatchError(ErrAlreadyUploaded))
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /stemcells"))
+		})
+
+		It("uploads a release it already has when fixing or rebasing", func() {
+			for _, opts := range []UploadOptions{
+				{Name: "bosh-warden-cpi", Version: "28", Fix: true},
+				{Name: "bosh-warden-cpi", Version: "28", Rebase: true},
+			} {
+				task, err := client.UploadReleaseURL("https://example.com/cpi.tgz", opts)
+				Expect(err).Should(BeNil())
+				Expect(task.ID).Should(Equal(7))
+				task, err = client.UploadReleaseReaderWithOptions(strings.NewReader("release"), 7, opts)
+				Expect(err).Should(BeNil())
+				Expect(task.ID).Should(Equal(7))
+			}
+			Expect(receivedRequests).ShouldNot(HaveKey("GET /releases"))
+		})
+
+		It("uploads a stemcell it already has when fixing", func() {
+			opts := UploadOptions{Name: "bosh-warden-boshlite-ubuntu-trusty-go_agent", Version: "3126", Fix: true}
+			task, err := client.UploadStemcellURL("https://example.com/stemcell.tgz", opts)
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(7))
+			task, err = client.UploadStemcellReaderWithOptions(strings.NewReader("stemcell"), 8, opts)
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(7))
+			Expect(receivedRequests).ShouldNot(HaveKey("GET /stemcells"))
+		})
+
+		It("skips a stemcell it already has when rebasing, as stemcells are never rebased", func() {
+			opts := UploadOptions{Name: "bosh-warden-boshlite-ubuntu-trusty-go_agent", Version: "3126", Rebase: true}
+			_, err := client.UploadStemcellURL("https://example.com/stemcell.tgz", opts)
+			Expect(err).Should(MatchError(ErrAlreadyUploaded))
+			Expect(receivedRequests).ShouldNot(HaveKey("POST /stemcells"))
+		})
+
+		It("reports the progress of a local upload", func() {
+			content := strings.Repeat("x", 100000)
+			var sent []int64
+			var total int64
+			var rate float64
+			_, err := client.UploadReleaseReaderWithOptions(strings.NewReader(content), int64(len(content)), UploadOptions{
+				Rebase: true,
+				Progress: func(s, t int64, r float64) {
+					sent = append(sent, s)
+					total, rate = t, r
+				},
+			})
+			Expect(err).Should(BeNil())
+			Expect(sent).ShouldNot(BeEmpty())
+			for i := 1; i < len(sent); i++ {
+				Expect(sent[i]).Should(BeNumerically(">", sent[i-1]))
+			}
+			Expect(sent[len(sent)-1]).Should(Equal(int64(100000)))
+			Expect(total).Should(Equal(int64(100000)))
+			Expect(rate).Should(BeNumerically(">", 0))
+			Expect(receivedRequests["POST /releases"].Query).Should(HaveKeyWithValue("rebase", []string{"true"}))
+		})
+	})
+})
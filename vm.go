@@ -0,0 +1,158 @@
+package gogobosh
+
+import (
+	"fmt"
+)
+
+// DeleteVM deletes the VM with the given CID from the IaaS. If it belongs to
+// an instance, the resurrector will recreate it.
+func (c *Client) DeleteVM(cid string) (Task, error) {
+	r := c.NewRequest("DELETE", "/vms/"+cid)
+	var task Task
+	err := c.DoRequestAndUnmarshal(r, &task)
+	if err != nil {
+		return Task{}, fmt.Errorf("error deleting VM %s: %w", cid, err)
+	}
+	return task, nil
+}
+
+// DeleteDeploymentVM deletes the VM with the given CID after checking that it
+// is one of the VMs of the deployment, which guards against deleting a VM of
+// the wrong deployment or director. Use force to skip the check.
+func (c *Client) DeleteDeploymentVM(deployment, cid string, force bool) (Task, error) {
+	if !force {
+		vms, err := c.GetDeploymentVMsWithOptions(deployment, VMsOptions{})
+		if err != nil {
+			return Task{}, fmt.Errorf("error checking VM %s belongs to deployment %s: %w", cid, deployment, err)
+		}
+		if _, ok := findVMByCID(vms, cid); !ok {
+			return Task{}, fmt.Errorf("refusing to delete VM %s as it is not part of deployment %s", cid, deployment)
+		}
+	}
+	return c.DeleteVM(cid)
+}
+
+// GetOrphanedVMs returns the VMs orphaned by the given BOSH. The VMs deleted
+// through DeleteOrphanedVMs are left out, even though the director keeps
+// listing them until they are removed by its next Cleanup.
+func (c *Client) GetOrphanedVMs() ([]OrphanedVM, error) {
+	r := c.NewRequest("GET", "/orphaned_vms")
+	var vms []OrphanedVM
+	err := c.DoRequestAndUnmarshal(r, &vms)
+	if err != nil {
+		return []OrphanedVM{}, fmt.Errorf("error requesting orphaned VMs: %w", err)
+	}
+
+	c.deletedOrphansLock.Lock()
+	defer c.deletedOrphansLock.Unlock()
+	orphans := []OrphanedVM{}
+	listed := map[string]bool{}
+	for _, vm := range vms {
+		if c.deletedOrphans[vm.CID] {
+			listed[vm.CID] = true
+			continue
+		}
+		orphans = append(orphans, vm)
+	}
+	// Forget the deleted VMs the director no longer lists
+	c.deletedOrphans = listed
+	return orphans, nil
+}
+
+// OrphanedVMCleanupPlan sorts orphaned VMs by whether they can safely be deleted
+type OrphanedVMCleanupPlan struct {
+	Removable []OrphanedVM
+	Kept      []KeptOrphanedVM
+}
+
+// KeptOrphanedVM is an orphaned VM that should not be cleaned up yet
+type KeptOrphanedVM struct {
+	VM     OrphanedVM
+	Reason string
+}
+
+// NewOrphanedVMCleanupPlan correlates the orphaned VMs with the current VMs of
+// their deployments, keyed by deployment name. An orphaned VM whose CID is
+// still used by a deployment is kept, as the director state is inconsistent
+// and deleting it would take down a live instance.
+func NewOrphanedVMCleanupPlan(orphans []OrphanedVM, current map[string][]VM) OrphanedVMCleanupPlan {
+	var plan OrphanedVMCleanupPlan
+	for _, orphan := range orphans {
+		if vm, ok := findVMByCID(current[orphan.Deployment], orphan.CID); ok {
+			plan.Kept = append(plan.Kept, KeptOrphanedVM{
+				VM:     orphan,
+				Reason: fmt.Sprintf("VM %s is still used by instance %s/%s", orphan.CID, vm.JobName, vm.ID),
+			})
+			continue
+		}
+		plan.Removable = append(plan.Removable, orphan)
+	}
+	return plan
+}
+
+// PlanOrphanedVMCleanup lists the orphaned VMs and the VMs of the deployments
+// they came from, and plans which of them can safely be deleted
+func (c *Client) PlanOrphanedVMCleanup() (OrphanedVMCleanupPlan, error) {
+	orphans, err := c.GetOrphanedVMs()
+	if err != nil {
+		return OrphanedVMCleanupPlan{}, err
+	}
+
+	deployments, err := c.GetDeployments()
+	if err != nil {
+		return OrphanedVMCleanupPlan{}, err
+	}
+	exists := map[string]bool{}
+	for _, d := range deployments {
+		exists[d.Name] = true
+	}
+
+	current := map[string][]VM{}
+	for _, orphan := range orphans {
+		if _, ok := current[orphan.Deployment]; ok || !exists[orphan.Deployment] {
+			continue
+		}
+		vms, err := c.GetDeploymentVMsWithOptions(orphan.Deployment, VMsOptions{})
+		if err != nil {
+			return OrphanedVMCleanupPlan{}, err
+		}
+		current[orphan.Deployment] = vms
+	}
+
+	return NewOrphanedVMCleanupPlan(orphans, current), nil
+}
+
+// DeleteOrphanedVMs deletes the Removable VMs of the plan and leaves the kept
+// ones alone, unlike Cleanup which deletes every orphaned VM. It stops at the
+// first VM that cannot be deleted and returns the tasks started so far.
+//
+// The director has no endpoint to delete a single orphaned VM, so the VMs are
+// deleted with DeleteVM, which leaves their orphaned VM records in place until
+// the next Cleanup. The client remembers the deleted VMs and leaves them out of
+// GetOrphanedVMs and PlanOrphanedVMCleanup meanwhile.
+func (c *Client) DeleteOrphanedVMs(plan OrphanedVMCleanupPlan) ([]Task, error) {
+	tasks := []Task{}
+	for _, vm := range plan.Removable {
+		task, err := c.DeleteVM(vm.CID)
+		if err != nil {
+			return tasks, err
+		}
+		c.deletedOrphansLock.Lock()
+		if c.deletedOrphans == nil {
+			c.deletedOrphans = map[string]bool{}
+		}
+		c.deletedOrphans[vm.CID] = true
+		c.deletedOrphansLock.Unlock()
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func findVMByCID(vms []VM, cid string) (VM, bool) {
+	for _, vm := range vms {
+		if vm.VMCID == cid {
+			return vm, true
+		}
+	}
+	return VM{}, false
+}
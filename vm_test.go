@@ -0,0 +1,147 @@
+package gogobosh_test
+
+import (
+	. "github.com/cloudfoundry-community/gogobosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VM", func() {
+	Describe("Test orphaned VMs", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"GET", "/orphaned_vms", orphanedVMs, ""},
+				{"GET", "/deployments", deployments, ""},
+				{"GET", "/deployments/cf-warden/vms", basicVMs, ""},
+				{"DELETE", "/vms/0d1e2f3a-4b5c-6d7e-8f9a-b0c1d2e3f4a5", "", "/tasks/2"},
+				{"DELETE", "/vms/6a5b4c3d-2e1f-0a9b-8c7d-6e5f4a3b2c1d", "", "/tasks/2"},
+				{"GET", "/tasks/2", task, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("can get orphaned VMs", func() {
+			vms, err := client.GetOrphanedVMs()
+			Expect(err).Should(BeNil())
+			Expect(vms).Should(HaveLen(3))
+			Expect(vms[1].CID).Should(Equal("0d1e2f3a-4b5c-6d7e-8f9a-b0c1d2e3f4a5"))
+			Expect(vms[1].AZ).Should(Equal("z2"))
+			Expect(vms[1].Deployment).Should(Equal("cf-warden"))
+			Expect(vms[1].Instance).Should(Equal("doppler_z1/9f0c2d4b-7a13-4c8e-b5d6-1e2f3a4b5c6d"))
+			Expect(vms[1].IPs).Should(Equal([]string{"10.244.0.150"}))
+			Expect(vms[1].OrphanedAt).Should(Equal("2022-08-03 22:58:02 UTC"))
+		})
+
+		It("keeps orphaned VMs that are still in use", func() {
+			plan, err := client.PlanOrphanedVMCleanup()
+			Expect(err).Should(BeNil())
+			Expect(plan.Removable).Should(HaveLen(2))
+			Expect(plan.Removable[0].CID).Should(Equal("0d1e2f3a-4b5c-6d7e-8f9a-b0c1d2e3f4a5"))
+			Expect(plan.Removable[1].Deployment).Should(Equal("deleted"))
+			Expect(plan.Kept).Should(HaveLen(1))
+			Expect(plan.Kept[0].VM.CID).Should(Equal("ec974048-3352-4ba4-669d-beab87b16bcb"))
+			Expect(plan.Kept[0].Reason).Should(ContainSubstring("doppler_z1/4a9278c8-e93a-4d6a-b22c-13560208da9e"))
+		})
+
+		It("plans the cleanup of orphaned VMs against the given VMs", func() {
+			orphans := []OrphanedVM{
+				{CID: "ec974048-3352-4ba4-669d-beab87b16bcb", Deployment: "cf-warden"},
+				{CID: "0d1e2f3a-4b5c-6d7e-8f9a-b0c1d2e3f4a5", Deployment: "cf-warden"},
+			}
+			plan := NewOrphanedVMCleanupPlan(orphans, map[string][]VM{
+				"cf-warden": {{VMCID: "ec974048-3352-4ba4-669d-beab87b16bcb", JobName: "doppler_z1", ID: "4a9278c8"}},
+			})
+			Expect(plan.Removable).Should(Equal(orphans[1:]))
+			Expect(plan.Kept).Should(Equal([]KeptOrphanedVM{
+				{VM: orphans[0], Reason: "VM ec974048-3352-4ba4-669d-beab87b16bcb is still used by instance doppler_z1/4a9278c8"},
+			}))
+		})
+
+		It("only deletes the removable VMs of the plan", func() {
+			plan, err := client.PlanOrphanedVMCleanup()
+			Expect(err).Should(BeNil())
+
+			tasks, err := client.DeleteOrphanedVMs(plan)
+			Expect(err).Should(BeNil())
+			Expect(tasks).Should(HaveLen(2))
+			Expect(receivedRequests).Should(HaveKey("DELETE /vms/0d1e2f3a-4b5c-6d7e-8f9a-b0c1d2e3f4a5"))
+			Expect(receivedRequests).Should(HaveKey("DELETE /vms/6a5b4c3d-2e1f-0a9b-8c7d-6e5f4a3b2c1d"))
+			Expect(receivedRequests).ShouldNot(HaveKey("DELETE /vms/ec974048-3352-4ba4-669d-beab87b16bcb"))
+		})
+
+		It("leaves the deleted VMs out of the orphaned VMs", func() {
+			plan, err := client.PlanOrphanedVMCleanup()
+			Expect(err).Should(BeNil())
+			_, err = client.DeleteOrphanedVMs(plan)
+			Expect(err).Should(BeNil())
+
+			vms, err := client.GetOrphanedVMs()
+			Expect(err).Should(BeNil())
+			Expect(vms).Should(HaveLen(1))
+			Expect(vms[0].CID).Should(Equal("ec974048-3352-4ba4-669d-beab87b16bcb"))
+
+			plan, err = client.PlanOrphanedVMCleanup()
+			Expect(err).Should(BeNil())
+			Expect(plan.Removable).Should(BeEmpty())
+			Expect(plan.Kept).Should(HaveLen(1))
+		})
+	})
+
+	Describe("Test delete VM", func() {
+		var client *Client
+
+		BeforeEach(func() {
+			setupMockRoutes([]MockRoute{
+				{"DELETE", "/vms/ec974048-3352-4ba4-669d-beab87b16bcb", "", "/tasks/2"},
+				{"DELETE", "/vms/0d1e2f3a-4b5c-6d7e-8f9a-b0c1d2e3f4a5", "", "/tasks/2"},
+				{"GET", "/tasks/2", task, ""},
+				{"GET", "/deployments/cf-warden/vms", basicVMs, ""},
+			}, "basic")
+			config := &Config{
+				BOSHAddress: server.URL,
+				Username:    "admin",
+				Password:    "admin",
+			}
+
+			client, _ = NewClient(config)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		It("can delete a VM by CID", func() {
+			task, err := client.DeleteVM("ec974048-3352-4ba4-669d-beab87b16bcb")
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(2))
+		})
+
+		It("deletes a VM of the deployment", func() {
+			task, err := client.DeleteDeploymentVM("cf-warden", "ec974048-3352-4ba4-669d-beab87b16bcb", false)
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(2))
+		})
+
+		It("refuses to delete a VM outside of the deployment unless forced", func() {
+			_, err := client.DeleteDeploymentVM("cf-warden", "0d1e2f3a-4b5c-6d7e-8f9a-b0c1d2e3f4a5", false)
+			Expect(err).Should(MatchError(ContainSubstring("not part of deployment cf-warden")))
+			Expect(receivedRequests).ShouldNot(HaveKey("DELETE /vms/0d1e2f3a-4b5c-6d7e-8f9a-b0c1d2e3f4a5"))
+
+			task, err := client.DeleteDeploymentVM("cf-warden", "0d1e2f3a-4b5c-6d7e-8f9a-b0c1d2e3f4a5", true)
+			Expect(err).Should(BeNil())
+			Expect(task.ID).Should(Equal(2))
+		})
+	})
+})